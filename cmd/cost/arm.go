@@ -0,0 +1,65 @@
+package cost
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kaytu-io/pennywise/cmd/flags"
+	"github.com/kaytu-io/pennywise/pkg/parser/arm"
+	azureLocation "github.com/kaytu-io/pennywise/pkg/parser/azurerm/location"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	usagePackage "github.com/kaytu-io/pennywise/pkg/usage"
+	"github.com/spf13/cobra"
+)
+
+var armCommand = &cobra.Command{
+	Use:   "arm",
+	Short: `Shows the costs by parsing an Azure Resource Manager template.`,
+	Long:  `Shows the costs by parsing an Azure Resource Manager (ARM) template evaluated with its parameters, templates built from bicep files are supported. Each nested deployment is shown as a module.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templatePath := flags.ReadStringFlag(cmd, "template")
+		template, err := arm.ReadTemplate(templatePath)
+		if err != nil {
+			return err
+		}
+		var parameters map[string]interface{}
+		if parametersPath := flags.ReadStringOptionalFlag(cmd, "parameters"); parametersPath != nil {
+			parameters, err = arm.ReadParameters(*parametersPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		location, err := azureLocation.Normalize(flags.ReadStringFlag(cmd, "location"))
+		if err != nil {
+			return err
+		}
+		opts := arm.Options{
+			Location:       location,
+			ResourceGroup:  flags.ReadStringFlag(cmd, "resource-group"),
+			DeploymentName: strings.TrimSuffix(filepath.Base(templatePath), filepath.Ext(templatePath)),
+		}
+		return estimateCosts(cmd, func(usage usagePackage.Usage) (submission, error) {
+			deployment, err := arm.Evaluate(template, parameters, opts, usage)
+			if err != nil {
+				return nil, err
+			}
+			for _, warning := range deployment.Warnings {
+				fmt.Fprintln(os.Stderr, warning)
+			}
+			if len(deployment.Unsupported) > 0 {
+				fmt.Fprintf(os.Stderr, "resource types not supported yet: %s\n", strings.Join(unique(deployment.Unsupported), ", "))
+			}
+
+			sub, err := schema.CreateSubmissionV2(deployment.Module)
+			if err != nil {
+				return nil, err
+			}
+			usagePackage.Warn(sub.UsageIssues(usage))
+			sub.Tool = &schema.IaCTool{Name: schema.ARMTool}
+			return sub, nil
+		})
+	},
+}
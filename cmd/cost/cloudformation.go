@@ -0,0 +1,64 @@
+package cost
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kaytu-io/pennywise/cmd/flags"
+	"github.com/kaytu-io/pennywise/pkg/parser/cloudformation"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	usagePackage "github.com/kaytu-io/pennywise/pkg/usage"
+	"github.com/spf13/cobra"
+)
+
+var cloudformationCommand = &cobra.Command{
+	Use:   "cloudformation",
+	Short: `Shows the costs by parsing a CloudFormation template.`,
+	Long:  `Shows the costs by parsing a CloudFormation template (json or yaml) evaluated with its parameters.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := cloudformation.Options{
+			Region:    flags.ReadStringFlag(cmd, "region"),
+			StackName: flags.ReadStringFlag(cmd, "stack-name"),
+		}
+		if parametersPath := flags.ReadStringOptionalFlag(cmd, "parameters"); parametersPath != nil {
+			parameters, err := cloudformation.ReadParameters(*parametersPath)
+			if err != nil {
+				return err
+			}
+			opts.Parameters = parameters
+		}
+
+		template, err := cloudformation.ParseTemplate(flags.ReadStringFlag(cmd, "template"), opts)
+		if err != nil {
+			return err
+		}
+		return estimateCosts(cmd, func(usage usagePackage.Usage) (submission, error) {
+			resources, unsupported := template.GetResources(opts.Region, usage)
+			if len(unsupported) > 0 {
+				fmt.Fprintf(os.Stderr, "resource types not supported yet: %s\n", strings.Join(unique(unsupported), ", "))
+			}
+
+			sub, err := schema.CreateSubmission(resources)
+			if err != nil {
+				return nil, err
+			}
+			usagePackage.Warn(sub.UsageIssues(usage))
+			sub.Tool = &schema.IaCTool{Name: schema.CloudFormationTool}
+			return sub, nil
+		})
+	},
+}
+
+// unique returns the values without duplicates keeping their order
+func unique(values []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -1,6 +1,11 @@
 package cost
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/kaytu-io/pennywise/cmd/flags"
+	"github.com/kaytu-io/pennywise/pkg/parser/aws"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	"github.com/spf13/cobra"
+)
 
 // CostCmd cost commands
 var CostCmd = &cobra.Command{
@@ -15,13 +20,61 @@ var CostCmd = &cobra.Command{
 func init() {
 	CostCmd.AddCommand(projectCommand)
 	projectCommand.Flags().String("json-path", "", "terraform plan json file path")
+	projectCommand.Flags().String("plan-path", "", "terraform plan file path, either the json or the binary file (-out of terraform plan)")
 	projectCommand.Flags().String("project-path", ".", "path to terraform project")
 	projectCommand.Flags().StringSlice("terraform-var-file", []string{}, "path to terraform variables file")
-	projectCommand.Flags().String("usage", "", "usage file path")
-	projectCommand.Flags().Bool("classic", false, "Show results in classic view (not interactive)")
+	projectCommand.Flags().StringSlice("env", []string{}, "environment variables (KEY=VALUE) used to evaluate the terraform or terragrunt project, ex: TF_VAR_region=us-east-1")
+	projectCommand.Flags().Bool("generate-plan", false, "generate the plan json by running terraform (or tofu) init, plan and show on the project path")
+	projectCommand.Flags().String("terraform-binary", "", "terraform or tofu binary used to generate the plan (looked up on PATH by default)")
+	projectCommand.Flags().String("workspace", "", "terraform workspace used to evaluate the project and generate the plan, defaults to the workspace of the project in the projects config")
+	projectCommand.Flags().Bool("no-backend", false, "run terraform init with -backend=false when generating the plan")
+	flags.AddUsageFlags(projectCommand)
+	flags.AddScenariosFlag(projectCommand)
+	flags.AddOutputFlags(projectCommand)
+
+	CostCmd.AddCommand(cloudformationCommand)
+	cloudformationCommand.Flags().String("template", "", "CloudFormation template file path (json or yaml)")
+	cloudformationCommand.MarkFlagRequired("template")
+	cloudformationCommand.Flags().String("parameters", "", "parameters file path, in the AWS CLI format or as a json object of parameter values")
+	cloudformationCommand.Flags().String("region", aws.DefaultRegion, "region the stack is deployed to")
+	cloudformationCommand.Flags().String("stack-name", "", "name of the stack, used as the value of AWS::StackName")
+	flags.AddUsageFlags(cloudformationCommand)
+	flags.AddScenariosFlag(cloudformationCommand)
+	flags.AddOutputFlags(cloudformationCommand)
+
+	CostCmd.AddCommand(armCommand)
+	armCommand.Flags().String("template", "", "ARM template file path, bicep files have to be built to json first (az bicep build)")
+	armCommand.MarkFlagRequired("template")
+	armCommand.Flags().String("parameters", "", "parameters file path")
+	armCommand.Flags().String("location", "eastus", "location of the resource group the template is deployed to, used as the value of resourceGroup().location")
+	armCommand.Flags().String("resource-group", "", "name of the resource group the template is deployed to")
+	flags.AddUsageFlags(armCommand)
+	flags.AddScenariosFlag(armCommand)
+	flags.AddOutputFlags(armCommand)
+
+	CostCmd.AddCommand(pulumiCommand)
+	pulumiCommand.Flags().String("preview-path", "", "path to the output of pulumi preview --json")
+	pulumiCommand.MarkFlagRequired("preview-path")
+	flags.AddUsageFlags(pulumiCommand)
+	flags.AddScenariosFlag(pulumiCommand)
+	flags.AddOutputFlags(pulumiCommand)
+
+	CostCmd.AddCommand(k8sCommand)
+	k8sCommand.Flags().StringSlice("manifests", []string{}, "kubernetes manifest files or directories, ex: the output of helm template")
+	k8sCommand.MarkFlagRequired("manifests")
+	k8sCommand.Flags().String("instance-type", "", "instance type of the nodes, ex: m5.large or Standard_D4s_v5")
+	k8sCommand.Flags().String("provider", string(schema.AWSProvider), "provider of the nodes instance type (aws or azurerm)")
+	k8sCommand.Flags().String("region", "", "region of the nodes, defaults to us-east-1 for aws and eastus for azurerm")
+	k8sCommand.Flags().String("project-path", "", "path to the terraform project of the node group, used if no instance type is given")
+	k8sCommand.Flags().String("node-group", "", "name or address of the node group in the terraform project (aws_eks_node_group, azurerm_kubernetes_cluster_node_pool or azurerm_kubernetes_cluster)")
+	k8sCommand.Flags().Float64("node-cpu", 0, "vCPUs of a node, required if the instance type is not known")
+	k8sCommand.Flags().Float64("node-memory", 0, "memory of a node in GiB, required if the instance type is not known")
+	k8sCommand.Flags().Int("nodes", 0, "number of nodes, defaults to the minimum number of nodes the workloads fit in")
+	k8sCommand.Flags().String("hpa-replicas", "min", "replicas of the workloads scaled by a HorizontalPodAutoscaler (min or max)")
+	flags.AddOutputFlags(k8sCommand)
 
 	CostCmd.AddCommand(submissionCommand)
 	submissionCommand.Flags().String("submission-id", "", "submission id")
 	submissionCommand.MarkFlagRequired("submission-id")
-	submissionCommand.Flags().Bool("classic", false, "Show results in classic view (not interactive)")
+	flags.AddOutputFlags(submissionCommand)
 }
@@ -0,0 +1,131 @@
+package cost
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kaytu-io/pennywise/cmd/flags"
+	"github.com/kaytu-io/pennywise/pkg"
+	"github.com/kaytu-io/pennywise/pkg/kubernetes"
+	outputCost "github.com/kaytu-io/pennywise/pkg/output/cost"
+	"github.com/kaytu-io/pennywise/pkg/parser/aws"
+	"github.com/kaytu-io/pennywise/pkg/parser/hcl"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	"github.com/kaytu-io/pennywise/pkg/server"
+	usagePackage "github.com/kaytu-io/pennywise/pkg/usage"
+	"github.com/spf13/cobra"
+)
+
+// defaultAzureLocation is the location of the azure nodes if no region is given
+const defaultAzureLocation = "eastus"
+
+var k8sCommand = &cobra.Command{
+	Use:   "k8s",
+	Short: `Shows the costs of Kubernetes workloads by parsing their manifests.`,
+	Long: `Shows the costs of Kubernetes workloads by parsing their manifests (or the output of helm template). The cost of a workload is the share of the nodes its pods request, replicas x max(cpu / node cpu, memory / node memory).
+The nodes are the instance type given or the node group of a terraform project. The workloads are shown grouped by namespace.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manifests := flags.ReadStringArrayFlag(cmd, "manifests")
+		if len(manifests) == 0 {
+			return fmt.Errorf("no manifests given")
+		}
+		objects, err := kubernetes.ReadManifests(manifests)
+		if err != nil {
+			return err
+		}
+		hpaReplicas := flags.ReadStringFlag(cmd, "hpa-replicas")
+		if hpaReplicas != "min" && hpaReplicas != "max" {
+			return fmt.Errorf("invalid hpa replicas %s, should be min or max", hpaReplicas)
+		}
+		period, err := flags.ReadPeriod(cmd)
+		if err != nil {
+			return err
+		}
+		currency, err := flags.ReadCurrency(cmd)
+		if err != nil {
+			return err
+		}
+		workloads, warnings, err := kubernetes.GetWorkloads(objects, hpaReplicas == "max")
+		if err != nil {
+			return err
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+		if len(workloads) == 0 {
+			return fmt.Errorf("no workloads found in the manifests")
+		}
+
+		node, err := getKubernetesNode(cmd)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "nodes: %s (%g vCPU, %g GiB) in %s\n", node.InstanceType, node.CPU, node.Memory, node.Region)
+
+		serverClient, err := server.NewPennywiseServerClient(pkg.DefaultServerAddress)
+		if err != nil {
+			return err
+		}
+		nodeResource := node.ResourceDef()
+		sub, err := schema.CreateSubmission([]schema.ResourceDef{nodeResource})
+		if err != nil {
+			return err
+		}
+		nodeState, err := serverClient.GetStateCost(*sub)
+		if err != nil {
+			return err
+		}
+		nodePrice, ok := nodeState.Resources[nodeResource.Address]
+		if !ok || len(nodePrice.Components) == 0 {
+			return fmt.Errorf("no price found for instance type %s in %s", node.InstanceType, node.Region)
+		}
+
+		state := kubernetes.Estimate(workloads, *node, nodePrice, int(flags.ReadInt64Flag(cmd, "nodes")))
+		if err := convertCosts(&state, currency); err != nil {
+			return err
+		}
+		if flags.ReadBooleanFlag(cmd, "classic") {
+			costString, err := state.ToClassicState().CostString(period)
+			if err != nil {
+				return err
+			}
+			fmt.Println(costString)
+			return nil
+		}
+		return outputCost.ShowStateCosts(&state, period, currency)
+	},
+}
+
+// getKubernetesNode returns the node of the instance type flag, or of the node group of the terraform project
+func getKubernetesNode(cmd *cobra.Command) (*kubernetes.Node, error) {
+	provider := schema.ProviderName(flags.ReadStringFlag(cmd, "provider"))
+	region := flags.ReadStringFlag(cmd, "region")
+	if region == "" {
+		region = aws.DefaultRegion
+		if provider == schema.AzureProvider {
+			region = defaultAzureLocation
+		}
+	}
+	cpu := flags.ReadFloat64Flag(cmd, "node-cpu")
+	memory := flags.ReadFloat64Flag(cmd, "node-memory")
+
+	if instanceType := flags.ReadStringOptionalFlag(cmd, "instance-type"); instanceType != nil {
+		return kubernetes.NewNode(provider, *instanceType, region, cpu, memory)
+	}
+	projectPath := flags.ReadStringOptionalFlag(cmd, "project-path")
+	if projectPath == nil {
+		return nil, fmt.Errorf("either the instance type or the terraform project of the node group has to be given")
+	}
+	project, err := hcl.ParseHclResources(*projectPath, usagePackage.Usage{}, hcl.Options{})
+	if err != nil {
+		return nil, err
+	}
+	group, err := kubernetes.GetNodeGroup(*project, flags.ReadStringFlag(cmd, "node-group"))
+	if err != nil {
+		return nil, err
+	}
+	if group.ProviderName == schema.AzureProvider && flags.ReadStringOptionalFlag(cmd, "region") == nil {
+		region = defaultAzureLocation
+	}
+	return kubernetes.NewNodeFromGroup(*group, region, cpu, memory)
+}
@@ -1,22 +1,20 @@
 package cost
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"github.com/kaytu-io/infracost/external/providers"
 	"github.com/kaytu-io/pennywise/cmd/cost/terraform"
 	"github.com/kaytu-io/pennywise/cmd/flags"
-	"github.com/kaytu-io/pennywise/pkg"
 	"github.com/kaytu-io/pennywise/pkg/cost"
 	outputCost "github.com/kaytu-io/pennywise/pkg/output/cost"
 	"github.com/kaytu-io/pennywise/pkg/parser/hcl"
+	"github.com/kaytu-io/pennywise/pkg/plan"
 	"github.com/kaytu-io/pennywise/pkg/schema"
-	"github.com/kaytu-io/pennywise/pkg/server"
 	usagePackage "github.com/kaytu-io/pennywise/pkg/usage"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v2"
+	"io"
 	"os"
-	"path/filepath"
 )
 
 var projectCommand = &cobra.Command{
@@ -24,137 +22,146 @@ var projectCommand = &cobra.Command{
 	Short: `Shows the costs by parsing a project resources.`,
 	Long:  `Shows the costs by parsing a project resources.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		usagePath := flags.ReadStringOptionalFlag(cmd, "usage")
-		var usage usagePackage.Usage
-		if usagePath != nil {
-			usageFile, err := os.Open(*usagePath)
-			if err != nil {
-				return fmt.Errorf("error while reading usage file %s", err)
-			}
-			defer usageFile.Close()
-
-			ext := filepath.Ext(*usagePath)
-			switch ext {
-			case ".json":
-				err = json.NewDecoder(usageFile).Decode(&usage)
-			case ".yaml", ".yml":
-				err = yaml.NewDecoder(usageFile).Decode(&usage)
-			default:
-				return fmt.Errorf("unsupported file format %s for usage file", ext)
-			}
-			if err != nil {
-				return fmt.Errorf("error while parsing usage file %s", err)
-			}
-
-		} else {
-			usage = usagePackage.Usage{}
+		planPath := flags.ReadStringOptionalFlag(cmd, "plan-path")
+		if planPath == nil {
+			planPath = flags.ReadStringOptionalFlag(cmd, "json-path")
 		}
-
-		classic := flags.ReadBooleanFlag(cmd, "classic")
-
-		jsonPath := flags.ReadStringOptionalFlag(cmd, "json-path")
 		projectPath := flags.ReadStringFlag(cmd, "project-path")
 		tfVarFiles := flags.ReadStringArrayFlag(cmd, "terraform-var-file")
-		if jsonPath != nil {
-			err := estimateTfPlanJson(classic, *jsonPath, usage, pkg.DefaultServerAddress)
-			if err != nil {
-				return err
-			}
-		} else {
-			err := estimateTerraformProject(classic, projectPath, usage, pkg.DefaultServerAddress, tfVarFiles)
-			if err != nil {
-				return err
+		generatePlan := flags.ReadBooleanFlag(cmd, "generate-plan")
+		workspace := flags.ReadStringFlag(cmd, "workspace")
+		if workspace == "" {
+			workspace = schema.GetProjectWorkspace(projectPath)
+		}
+		env, err := flags.ReadKeyValueArrayFlag(cmd, "env")
+		if err != nil {
+			return err
+		}
+		planOptions := plan.Options{
+			Dir:       projectPath,
+			Binary:    flags.ReadStringFlag(cmd, "terraform-binary"),
+			VarFiles:  tfVarFiles,
+			Env:       env,
+			Workspace: workspace,
+			NoBackend: flags.ReadBooleanFlag(cmd, "no-backend"),
+		}
+		if planPath != nil || generatePlan {
+			var planJson []byte
+			if planPath != nil {
+				planJson, err = plan.Read(*planPath, planOptions)
+				if err != nil {
+					return err
+				}
+			} else {
+				stacks, err := hcl.GetCDKTFStacks(projectPath)
+				if err != nil {
+					return err
+				}
+				if len(stacks) > 0 {
+					return estimateCosts(cmd, func(usage usagePackage.Usage) (submission, error) {
+						return cdktfPlansSubmission(stacks, planOptions, usage)
+					})
+				}
+				planJson, err = plan.Generate(planOptions)
+				if err != nil {
+					return err
+				}
 			}
+			return estimateCosts(cmd, func(usage usagePackage.Usage) (submission, error) {
+				return tfPlanSubmission(bytes.NewReader(planJson), workspace, usage)
+			})
 		}
-		return nil
+		return estimateCosts(cmd, func(usage usagePackage.Usage) (submission, error) {
+			return terraformProjectSubmission(projectPath, usage, hcl.Options{VarFiles: tfVarFiles, Env: env, Workspace: workspace})
+		})
 	},
 }
 
-func estimateTfPlanJson(classic bool, jsonPath string, usage usagePackage.Usage, ServerClientAddress string) error {
-	file, err := os.Open(jsonPath)
+// tfPlanSubmission returns the submission of the resources of the terraform plan
+func tfPlanSubmission(planJson io.Reader, workspace string, usage usagePackage.Usage) (*schema.Submission, error) {
+	resources, tool, err := terraform.ParseTerraformPlanJson(planJson, usage)
 	if err != nil {
-		return err
-	}
-	resources, err := terraform.ParseTerraformPlanJson(file, usage)
-	if err != nil {
-		return err
-	}
-	serverClient, err := server.NewPennywiseServerClient(ServerClientAddress)
-	if err != nil {
-		return err
+		return nil, err
 	}
 	sub, err := schema.CreateSubmission(resources)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = sub.StoreAsFile()
+	usagePackage.Warn(sub.UsageIssues(usage))
+	sub.Workspace = workspace
+	sub.Tool = tool
+	return sub, nil
+}
+
+// cdktfPlansSubmission generates the plan of every CDKTF stack and returns their submission, each stack is a module
+func cdktfPlansSubmission(stacks []hcl.CDKTFStack, opts plan.Options, usage usagePackage.Usage) (*schema.SubmissionV2, error) {
+	projects, tool, err := terraform.ParseCDKTFPlans(stacks, opts, usage)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	state, err := serverClient.GetStateCost(*sub)
+	sub, err := schema.CreateSubmissionV2(*projects)
 	if err != nil {
-		return err
-	}
-	if classic {
-		costString, err := state.CostString()
-		if err != nil {
-			return err
-		}
-		fmt.Println(costString)
-		fmt.Println("To learn how to use usage open:\nhttps://github.com/kaytu-io/pennywise/blob/main/docs/usage.md")
-	} else {
-		modularState := cost.ModularState{
-			Resources: state.Resources,
-		}
-		err = outputCost.ShowStateCosts(&modularState)
-		if err != nil {
-			return err
-		}
+		return nil, err
 	}
-	return nil
+	usagePackage.Warn(sub.UsageIssues(usage))
+	sub.Workspace = opts.Workspace
+	sub.Tool = tool
+	return sub, nil
 }
 
-func estimateTerraformProject(classic bool, projectPath string, usage usagePackage.Usage, ServerClientAddress string, tfVarFiles []string) error {
+// terraformProjectSubmission parses the terraform, terragrunt or CDKTF project and returns its submission
+func terraformProjectSubmission(projectPath string, usage usagePackage.Usage, opts hcl.Options) (*schema.SubmissionV2, error) {
+	stacks, err := hcl.GetCDKTFStacks(projectPath)
+	if err != nil {
+		return nil, err
+	}
 	var projects *schema.ModuleDef
-	var err error
-	if providers.IsTerragruntNestedDir(projectPath, 5) {
+	if len(stacks) > 0 {
+		fmt.Println("cdktf project...")
+		projects, err = hcl.ParseCDKTFProject(stacks, projectPath, usage, opts)
+	} else if providers.IsTerragruntNestedDir(projectPath, 5) {
 		fmt.Println("terragrunt project...")
-		projects, err = hcl.ParseTerragruntProject(projectPath, usage)
+		projects, err = hcl.ParseTerragruntProject(projectPath, usage, opts)
 	} else {
-		projects, err = hcl.ParseHclResources(projectPath, usage, tfVarFiles)
-	}
-	if err != nil {
-		return err
+		projects, err = hcl.ParseHclResources(projectPath, usage, opts)
 	}
-	serverClient, err := server.NewPennywiseServerClient(ServerClientAddress)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	sub, err := schema.CreateSubmissionV2(*projects)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = sub.StoreAsFile()
-	if err != nil {
-		return err
-	}
-	state, err := serverClient.GetStateCostV2(*sub)
-	if err != nil {
+	usagePackage.Warn(sub.UsageIssues(usage))
+	sub.Workspace = opts.Workspace
+	return sub, nil
+}
+
+// showCosts shows the costs of the state for the period in the currency, as a table in classic mode
+func showCosts(classic bool, period cost.Period, currency string, state *cost.ModularState) error {
+	if err := convertCosts(state, currency); err != nil {
 		return err
 	}
 	if classic {
-		costString, err := state.ToClassicState().CostString()
+		costString, err := state.ToClassicState().CostString(period)
 		if err != nil {
 			return err
 		}
 		fmt.Println(costString)
 		fmt.Println("To learn how to use usage open:\nhttps://github.com/kaytu-io/pennywise/blob/main/docs/usage.md")
-	} else {
-		err = outputCost.ShowStateCosts(state)
-		if err != nil {
-			return err
-		}
+		return nil
 	}
+	return outputCost.ShowStateCosts(state, period, currency)
+}
 
+// convertCosts converts the costs of the state to the currency, the costs of the resources priced in other currencies
+// are converted as well
+func convertCosts(state *cost.ModularState, currency string) error {
+	if err := state.Convert(currency); err != nil {
+		return err
+	}
+	if currency != cost.DefaultCurrency {
+		fmt.Fprintf(os.Stderr, "costs converted to %s with the exchange rates of %s\n", currency, cost.Rates.Date)
+	}
 	return nil
 }
@@ -0,0 +1,40 @@
+package cost
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kaytu-io/pennywise/cmd/flags"
+	"github.com/kaytu-io/pennywise/pkg/parser/pulumi"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	usagePackage "github.com/kaytu-io/pennywise/pkg/usage"
+	"github.com/spf13/cobra"
+)
+
+var pulumiCommand = &cobra.Command{
+	Use:   "pulumi",
+	Short: `Shows the costs by parsing a Pulumi preview.`,
+	Long:  `Shows the costs by parsing the output of pulumi preview --json. AWS and Azure Native resources are supported, component resources are shown as modules.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		preview, err := pulumi.ReadPreview(flags.ReadStringFlag(cmd, "preview-path"))
+		if err != nil {
+			return err
+		}
+		return estimateCosts(cmd, func(usage usagePackage.Usage) (submission, error) {
+			module, unsupported := preview.GetModule(usage)
+			if len(unsupported) > 0 {
+				fmt.Fprintf(os.Stderr, "resource types not supported yet: %s\n", strings.Join(unsupported, ", "))
+			}
+
+			sub, err := schema.CreateSubmissionV2(*module)
+			if err != nil {
+				return nil, err
+			}
+			usagePackage.Warn(sub.UsageIssues(usage))
+			sub.Workspace = preview.Stack()
+			sub.Tool = &schema.IaCTool{Name: schema.PulumiTool}
+			return sub, nil
+		})
+	},
+}
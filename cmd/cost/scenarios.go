@@ -0,0 +1,113 @@
+package cost
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kaytu-io/pennywise/cmd/flags"
+	"github.com/kaytu-io/pennywise/pkg"
+	"github.com/kaytu-io/pennywise/pkg/cost"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	"github.com/kaytu-io/pennywise/pkg/server"
+	usagePackage "github.com/kaytu-io/pennywise/pkg/usage"
+	"github.com/spf13/cobra"
+)
+
+// submission is the submission of the parsed resources, *schema.Submission or *schema.SubmissionV2
+type submission interface {
+	StoreAsFile() error
+	SetUsage(usage usagePackage.Usage)
+}
+
+// estimator parses the resources with the usage and returns their submission
+type estimator func(usage usagePackage.Usage) (submission, error)
+
+// estimateCosts estimates the costs with the usage of the flags and shows them, the submission is stored as the
+// base of the diffs. With --scenarios the resources are parsed once and priced with the usage of every profile of
+// the usage files, their costs are shown side by side and no submission is stored.
+func estimateCosts(cmd *cobra.Command, estimate estimator) error {
+	period, err := flags.ReadPeriod(cmd)
+	if err != nil {
+		return err
+	}
+	currency, err := flags.ReadCurrency(cmd)
+	if err != nil {
+		return err
+	}
+	scenariosMode := flags.ReadBooleanFlag(cmd, "scenarios")
+	var usage usagePackage.Usage
+	var usageScenarios []flags.UsageScenario
+	if scenariosMode {
+		usage, usageScenarios, err = flags.ReadUsageScenarios(cmd)
+	} else {
+		usage, err = flags.ReadUsage(cmd)
+	}
+	if err != nil {
+		return err
+	}
+	sub, err := estimate(usage)
+	if err != nil {
+		return err
+	}
+	if !scenariosMode {
+		if err := sub.StoreAsFile(); err != nil {
+			return err
+		}
+		state, err := submissionCost(sub, pkg.DefaultServerAddress)
+		if err != nil {
+			return err
+		}
+		return showCosts(flags.ReadBooleanFlag(cmd, "classic"), period, currency, state)
+	}
+
+	var scenarios []cost.Scenario
+	for _, s := range usageScenarios {
+		fmt.Fprintf(os.Stderr, "estimating the %s scenario...\n", s.Profile)
+		sub.SetUsage(s.Usage)
+		state, err := submissionCost(sub, pkg.DefaultServerAddress)
+		if err != nil {
+			return fmt.Errorf("failed to estimate the %s scenario: %w", s.Profile, err)
+		}
+		scenarios = append(scenarios, cost.Scenario{Name: s.Profile, State: state})
+	}
+	for _, s := range scenarios {
+		if err := s.State.Convert(currency); err != nil {
+			return err
+		}
+	}
+	if currency != cost.DefaultCurrency {
+		fmt.Fprintf(os.Stderr, "costs converted to %s with the exchange rates of %s\n", currency, cost.Rates.Date)
+	}
+	scenariosString, err := cost.ScenariosString(scenarios, period)
+	if err != nil {
+		return err
+	}
+	fmt.Println(scenariosString)
+	return nil
+}
+
+// submissionCost gets the costs of the submission from the server
+func submissionCost(sub submission, ServerClientAddress string) (*cost.ModularState, error) {
+	serverClient, err := server.NewPennywiseServerClient(ServerClientAddress)
+	if err != nil {
+		return nil, err
+	}
+	switch s := sub.(type) {
+	case *schema.Submission:
+		state, err := serverClient.GetStateCost(*s)
+		if err != nil {
+			return nil, err
+		}
+		s.AddDiagnostics(state)
+		return &cost.ModularState{Resources: state.Resources}, nil
+	case *schema.SubmissionV2:
+		state, err := serverClient.GetStateCostV2(*s)
+		if err != nil {
+			return nil, err
+		}
+		s.AddDiagnostics(state)
+		return state, nil
+	default:
+		return nil, fmt.Errorf("unsupported submission %T", sub)
+	}
+}
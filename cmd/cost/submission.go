@@ -1,10 +1,9 @@
 package cost
 
 import (
-	"fmt"
 	"github.com/kaytu-io/pennywise/cmd/flags"
 	"github.com/kaytu-io/pennywise/pkg"
-	outputCost "github.com/kaytu-io/pennywise/pkg/output/cost"
+	"github.com/kaytu-io/pennywise/pkg/cost"
 	"github.com/kaytu-io/pennywise/pkg/schema"
 	"github.com/kaytu-io/pennywise/pkg/server"
 	"github.com/spf13/cobra"
@@ -16,9 +15,17 @@ var submissionCommand = &cobra.Command{
 	Long:  `Shows a submission cost.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		classic := flags.ReadBooleanFlag(cmd, "classic")
+		period, err := flags.ReadPeriod(cmd)
+		if err != nil {
+			return err
+		}
+		currency, err := flags.ReadCurrency(cmd)
+		if err != nil {
+			return err
+		}
 
 		submissionId := flags.ReadStringFlag(cmd, "submission-id")
-		err := estimateSubmission(classic, submissionId, pkg.DefaultServerAddress)
+		err = estimateSubmission(classic, period, currency, submissionId, pkg.DefaultServerAddress)
 		if err != nil {
 			return err
 		}
@@ -27,7 +34,7 @@ var submissionCommand = &cobra.Command{
 	},
 }
 
-func estimateSubmission(classic bool, submissionId string, ServerClientAddress string) error {
+func estimateSubmission(classic bool, period cost.Period, currency string, submissionId string, ServerClientAddress string) error {
 	serverClient, err := server.NewPennywiseServerClient(ServerClientAddress)
 	if err != nil {
 		return err
@@ -40,19 +47,6 @@ func estimateSubmission(classic bool, submissionId string, ServerClientAddress s
 	if err != nil {
 		return err
 	}
-	if classic {
-		costString, err := state.ToClassicState().CostString()
-		if err != nil {
-			return err
-		}
-		fmt.Println(costString)
-		fmt.Println("To learn how to use usage open:\nhttps://github.com/kaytu-io/pennywise/blob/main/docs/usage.md")
-	} else {
-		err = outputCost.ShowStateCosts(state)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	sub.AddDiagnostics(state)
+	return showCosts(classic, period, currency, state)
 }
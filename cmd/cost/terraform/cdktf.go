@@ -0,0 +1,39 @@
+package terraform
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/kaytu-io/pennywise/pkg/parser/hcl"
+	"github.com/kaytu-io/pennywise/pkg/plan"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	"github.com/kaytu-io/pennywise/pkg/usage"
+)
+
+// ParseCDKTFPlans generates the plan of every CDKTF stack and returns the stacks as child modules
+// with the stack names as their addresses. The opts directory is replaced by the stack directories.
+func ParseCDKTFPlans(stacks []hcl.CDKTFStack, opts plan.Options, u usage.Usage) (*schema.ModuleDef, *schema.IaCTool, error) {
+	var projectsModule schema.ModuleDef
+	var tool *schema.IaCTool
+	for _, stack := range stacks {
+		opts.Dir = stack.Dir
+		planJson, err := plan.Generate(opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate the plan of stack %s: %w", stack.Name, err)
+		}
+		resources, stackTool, err := ParseTerraformPlanJson(bytes.NewReader(planJson), u)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse the plan of stack %s: %w", stack.Name, err)
+		}
+		for i := range resources {
+			resources[i].UsageAddress = resources[i].GetUsageAddress()
+			resources[i].Address = stack.Name + "." + resources[i].Address
+		}
+		tool = stackTool
+		projectsModule.ChildModules = append(projectsModule.ChildModules, schema.ModuleDef{
+			Address:   stack.Name,
+			Resources: resources,
+		})
+	}
+	return &projectsModule, tool, nil
+}
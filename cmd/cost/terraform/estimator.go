@@ -1,8 +1,9 @@
 package terraform
 
 import (
-	"github.com/kaytu-io/pennywise/pkg/parser/aws"
-	"github.com/kaytu-io/pennywise/pkg/parser/azurerm"
+	"fmt"
+	gcpregion "github.com/kaytu-io/pennywise/pkg/parser/google/region"
+	"github.com/kaytu-io/pennywise/pkg/parser/registry"
 	terraform2 "github.com/kaytu-io/pennywise/pkg/parser/terraform"
 	"github.com/kaytu-io/pennywise/pkg/schema"
 	"github.com/kaytu-io/pennywise/pkg/usage"
@@ -17,34 +18,61 @@ var (
 // ParseTerraformPlanJson is a helper function that reads a Terraform plan json file using the provided io.Reader,
 // calculates the costs of the resources and show them.
 // It uses the Backend to retrieve the pricing data.
-func ParseTerraformPlanJson(plan io.Reader, u usage.Usage) ([]schema.ResourceDef, error) {
-	providerInitializers := []terraform2.ProviderInitializer{
-		aws.TerraformProviderInitializer,
-		azurerm.TerraformProviderInitializer,
-	}
-
-	tfplan := terraform2.NewPlan(providerInitializers...)
+// The tool (terraform or opentofu) that generated the plan is returned along with the resources.
+func ParseTerraformPlanJson(plan io.Reader, u usage.Usage) ([]schema.ResourceDef, *schema.IaCTool, error) {
+	tfplan := terraform2.NewPlan(registry.Initializers()...)
 	if err := tfplan.Read(plan); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	tfplan.SetUsage(u)
-	var defaultRegion string
+	// Every provider configuration has its own default region, keyed by the provider type and its alias
+	// (ex: aws.west) as the resources reference their configuration. The configurations without alias are
+	// also the default of the other types of the provider (ex: google-beta for google).
+	defaultRegions := make(map[string]string)
 	for _, config := range tfplan.Configuration.ProviderConfig {
-		for key, value := range config.Expressions {
-			if _, ok := value.ConstantValue.(string); ok && key == "region" {
-				defaultRegion = value.ConstantValue.(string)
-			}
+		p, ok := registry.Lookup(config.Name)
+		if !ok {
+			continue
+		}
+		var region string
+		if r, ok := config.Expressions["region"].ConstantValue.(string); ok {
+			region = r
+		} else if zone, ok := config.Expressions["zone"].ConstantValue.(string); ok {
+			// The Google provider can be configured with a zone only
+			region = gcpregion.FromConfig("", zone)
+		}
+		if region == "" {
+			continue
+		}
+		if config.Alias != "" {
+			defaultRegions[fmt.Sprintf("%s.%s", config.Name, config.Alias)] = region
+			continue
+		}
+		defaultRegions[config.Name] = region
+		if _, ok := defaultRegions[string(p.Name)]; !ok {
+			defaultRegions[string(p.Name)] = region
 		}
 	}
 
 	plannedQueries, err := tfplan.ExtractPlannedQueries()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	var resources []schema.ResourceDef
 	for _, rs := range plannedQueries {
-		res := rs.ToResource(defaultRegion)
+		res := rs.ToResource("")
+		if p, ok := registry.Lookup(string(res.ProviderName)); ok {
+			res.RegionCode = defaultRegions[rs.ProviderConfigKey]
+			if res.RegionCode == "" {
+				res.RegionCode = defaultRegions[string(p.Name)]
+			}
+			if res.RegionCode == "" {
+				res.RegionCode = p.DefaultRegion
+			}
+			p.Resolve(&res)
+		}
 		resources = append(resources, res)
 	}
-	return resources, nil
+	tool := tfplan.Tool()
+	return resources, &tool, nil
 }
@@ -1,6 +1,9 @@
 package diff
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/kaytu-io/pennywise/cmd/flags"
+	"github.com/spf13/cobra"
+)
 
 // DiffCmd diff commands
 var DiffCmd = &cobra.Command{
@@ -15,16 +18,29 @@ var DiffCmd = &cobra.Command{
 func init() {
 	DiffCmd.AddCommand(projectCommand)
 	projectCommand.Flags().String("json-path", "", "terraform plan json file path")
+	projectCommand.Flags().String("plan-path", "", "terraform plan file path, either the json or the binary file (-out of terraform plan)")
 	projectCommand.Flags().String("project-path", ".", "path to terraform project")
 	projectCommand.Flags().StringSlice("terraform-var-file", []string{}, "path to terraform variables file")
-	projectCommand.Flags().String("usage", "", "usage file path")
-	projectCommand.Flags().Bool("classic", false, "Show results in classic view (not interactive)")
+	projectCommand.Flags().StringSlice("env", []string{}, "environment variables (KEY=VALUE) used to evaluate the terraform or terragrunt project, ex: TF_VAR_region=us-east-1")
+	projectCommand.Flags().Bool("generate-plan", false, "generate the plan json by running terraform (or tofu) init, plan and show on the project path")
+	projectCommand.Flags().String("terraform-binary", "", "terraform or tofu binary used to generate the plan (looked up on PATH by default)")
+	projectCommand.Flags().String("workspace", "", "terraform workspace used to evaluate the project and generate the plan, defaults to the workspace of the project in the projects config")
+	projectCommand.Flags().Bool("no-backend", false, "run terraform init with -backend=false when generating the plan")
+	flags.AddUsageFlags(projectCommand)
+	flags.AddOutputFlags(projectCommand)
 	projectCommand.Flags().String("compare-to", "", "submission id to compare other submission with (latest submission by default)")
 
+	DiffCmd.AddCommand(pulumiCommand)
+	pulumiCommand.Flags().String("preview-path", "", "path to the output of pulumi preview --json")
+	pulumiCommand.MarkFlagRequired("preview-path")
+	flags.AddUsageFlags(pulumiCommand)
+	flags.AddOutputFlags(pulumiCommand)
+	pulumiCommand.Flags().String("compare-to", "", "submission id to compare other submission with (latest submission of the stack by default)")
+
 	DiffCmd.AddCommand(submissionCommand)
 	submissionCommand.Flags().String("submission-id", "", "submission id")
 	submissionCommand.MarkFlagRequired("submission-id")
 	submissionCommand.Flags().String("compare-to", "", "submission id to compare other submission with")
 	submissionCommand.MarkFlagRequired("compare-to")
-	submissionCommand.Flags().Bool("classic", false, "Show results in classic view (not interactive)")
+	flags.AddOutputFlags(submissionCommand)
 }
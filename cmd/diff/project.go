@@ -1,21 +1,22 @@
 package diff
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"github.com/kaytu-io/infracost/external/providers"
 	"github.com/kaytu-io/pennywise/cmd/cost/terraform"
 	"github.com/kaytu-io/pennywise/cmd/flags"
 	"github.com/kaytu-io/pennywise/pkg"
+	"github.com/kaytu-io/pennywise/pkg/cost"
 	outputDiff "github.com/kaytu-io/pennywise/pkg/output/diff"
 	"github.com/kaytu-io/pennywise/pkg/parser/hcl"
+	"github.com/kaytu-io/pennywise/pkg/plan"
 	"github.com/kaytu-io/pennywise/pkg/schema"
 	"github.com/kaytu-io/pennywise/pkg/server"
 	usagePackage "github.com/kaytu-io/pennywise/pkg/usage"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v2"
+	"io"
 	"os"
-	"path/filepath"
 )
 
 var projectCommand = &cobra.Command{
@@ -23,45 +24,72 @@ var projectCommand = &cobra.Command{
 	Short: `Shows the costs by parsing a project resources.`,
 	Long:  `Shows the costs by parsing a project resources.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		usagePath := flags.ReadStringOptionalFlag(cmd, "usage")
-		var usage usagePackage.Usage
-		if usagePath != nil {
-			usageFile, err := os.Open(*usagePath)
-			if err != nil {
-				return fmt.Errorf("error while reading usage file %s", err)
-			}
-			defer usageFile.Close()
-
-			ext := filepath.Ext(*usagePath)
-			switch ext {
-			case ".json":
-				err = json.NewDecoder(usageFile).Decode(&usage)
-			case ".yaml", ".yml":
-				err = yaml.NewDecoder(usageFile).Decode(&usage)
-			default:
-				return fmt.Errorf("unsupported file format %s for usage file", ext)
-			}
-			if err != nil {
-				return fmt.Errorf("error while parsing usage file %s", err)
-			}
-
-		} else {
-			usage = usagePackage.Usage{}
+		usage, err := flags.ReadUsage(cmd)
+		if err != nil {
+			return err
 		}
 
 		classic := flags.ReadBooleanFlag(cmd, "classic")
+		period, err := flags.ReadPeriod(cmd)
+		if err != nil {
+			return err
+		}
+		currency, err := flags.ReadCurrency(cmd)
+		if err != nil {
+			return err
+		}
 		compareTo := flags.ReadStringFlag(cmd, "compare-to")
 
-		jsonPath := flags.ReadStringOptionalFlag(cmd, "json-path")
+		planPath := flags.ReadStringOptionalFlag(cmd, "plan-path")
+		if planPath == nil {
+			planPath = flags.ReadStringOptionalFlag(cmd, "json-path")
+		}
 		projectPath := flags.ReadStringFlag(cmd, "project-path")
 		tfVarFiles := flags.ReadStringArrayFlag(cmd, "terraform-var-file")
-		if jsonPath != nil {
-			err := tfPlanJsonDiff(classic, *jsonPath, compareTo, usage, pkg.DefaultServerAddress)
+		generatePlan := flags.ReadBooleanFlag(cmd, "generate-plan")
+		workspace := flags.ReadStringFlag(cmd, "workspace")
+		if workspace == "" {
+			workspace = schema.GetProjectWorkspace(projectPath)
+		}
+		env, err := flags.ReadKeyValueArrayFlag(cmd, "env")
+		if err != nil {
+			return err
+		}
+		planOptions := plan.Options{
+			Dir:       projectPath,
+			Binary:    flags.ReadStringFlag(cmd, "terraform-binary"),
+			VarFiles:  tfVarFiles,
+			Env:       env,
+			Workspace: workspace,
+			NoBackend: flags.ReadBooleanFlag(cmd, "no-backend"),
+		}
+		if planPath != nil {
+			planJson, err := plan.Read(*planPath, planOptions)
+			if err != nil {
+				return err
+			}
+			err = tfPlanDiff(classic, period, currency, bytes.NewReader(planJson), compareTo, workspace, usage, pkg.DefaultServerAddress)
+			if err != nil {
+				return err
+			}
+		} else if generatePlan {
+			stacks, err := hcl.GetCDKTFStacks(projectPath)
+			if err != nil {
+				return err
+			}
+			if len(stacks) > 0 {
+				return cdktfPlansDiff(classic, period, currency, stacks, planOptions, compareTo, usage, pkg.DefaultServerAddress)
+			}
+			planJson, err := plan.Generate(planOptions)
+			if err != nil {
+				return err
+			}
+			err = tfPlanDiff(classic, period, currency, bytes.NewReader(planJson), compareTo, workspace, usage, pkg.DefaultServerAddress)
 			if err != nil {
 				return err
 			}
 		} else {
-			err := terraformProjectDiff(classic, projectPath, compareTo, usage, pkg.DefaultServerAddress, tfVarFiles)
+			err := terraformProjectDiff(classic, period, currency, projectPath, compareTo, usage, pkg.DefaultServerAddress, hcl.Options{VarFiles: tfVarFiles, Env: env, Workspace: workspace})
 			if err != nil {
 				return err
 			}
@@ -70,15 +98,11 @@ var projectCommand = &cobra.Command{
 	},
 }
 
-func tfPlanJsonDiff(classic bool, jsonPath string, compareToId string, usage usagePackage.Usage, ServerClientAddress string) error {
+func tfPlanDiff(classic bool, period cost.Period, currency string, planJson io.Reader, compareToId string, workspace string, usage usagePackage.Usage, ServerClientAddress string) error {
 	if classic {
 		return fmt.Errorf("classic view not available for diff")
 	}
-	file, err := os.Open(jsonPath)
-	if err != nil {
-		return err
-	}
-	resources, err := terraform.ParseTerraformPlanJson(file, usage)
+	resources, tool, err := terraform.ParseTerraformPlanJson(planJson, usage)
 	if err != nil {
 		return err
 	}
@@ -89,7 +113,7 @@ func tfPlanJsonDiff(classic bool, jsonPath string, compareToId string, usage usa
 
 	var compareTo *schema.Submission
 	if compareToId == "" {
-		compareTo, err = schema.GetLatestSubmission()
+		compareTo, err = schema.GetLatestSubmission(workspace)
 		if err != nil {
 			return err
 		}
@@ -104,6 +128,9 @@ func tfPlanJsonDiff(classic bool, jsonPath string, compareToId string, usage usa
 	if err != nil {
 		return err
 	}
+	usagePackage.Warn(sub.UsageIssues(usage))
+	sub.Workspace = workspace
+	sub.Tool = tool
 	err = sub.StoreAsFile()
 	if err != nil {
 		return err
@@ -122,28 +149,66 @@ func tfPlanJsonDiff(classic bool, jsonPath string, compareToId string, usage usa
 		PriorCost: stateDiff.PriorCost,
 		NewCost:   stateDiff.NewCost,
 	}
-	err = outputDiff.ShowStateCosts(&modularShowDiff)
+	sub.AddDiffDiagnostics(&modularShowDiff)
+	err = showDiff(&modularShowDiff, period, currency)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func terraformProjectDiff(classic bool, projectPath string, compareToId string, usage usagePackage.Usage, ServerClientAddress string, tfVarFiles []string) error {
+// cdktfPlansDiff generates the plan of every CDKTF stack and shows their costs diff, each stack is a module
+func cdktfPlansDiff(classic bool, period cost.Period, currency string, stacks []hcl.CDKTFStack, opts plan.Options, compareToId string, usage usagePackage.Usage, ServerClientAddress string) error {
 	if classic {
 		return fmt.Errorf("classic view not available for diff")
 	}
+	project, tool, err := terraform.ParseCDKTFPlans(stacks, opts, usage)
+	if err != nil {
+		return err
+	}
+	sub, err := schema.CreateSubmissionV2(*project)
+	if err != nil {
+		return err
+	}
+	usagePackage.Warn(sub.UsageIssues(usage))
+	sub.Workspace = opts.Workspace
+	sub.Tool = tool
+	return storeAndDiffSubmissionV2(sub, compareToId, period, currency, ServerClientAddress)
+}
+
+func terraformProjectDiff(classic bool, period cost.Period, currency string, projectPath string, compareToId string, usage usagePackage.Usage, ServerClientAddress string, opts hcl.Options) error {
+	if classic {
+		return fmt.Errorf("classic view not available for diff")
+	}
+	stacks, err := hcl.GetCDKTFStacks(projectPath)
+	if err != nil {
+		return err
+	}
 	var project *schema.ModuleDef
-	var err error
-	if providers.IsTerragruntNestedDir(projectPath, 5) {
+	if len(stacks) > 0 {
+		fmt.Println("cdktf project...")
+		project, err = hcl.ParseCDKTFProject(stacks, projectPath, usage, opts)
+	} else if providers.IsTerragruntNestedDir(projectPath, 5) {
 		fmt.Println("terragrunt project...")
-		project, err = hcl.ParseTerragruntProject(projectPath, usage)
+		project, err = hcl.ParseTerragruntProject(projectPath, usage, opts)
 	} else {
-		project, err = hcl.ParseHclResources(projectPath, usage, tfVarFiles)
+		project, err = hcl.ParseHclResources(projectPath, usage, opts)
+	}
+	if err != nil {
+		return err
 	}
+	sub, err := schema.CreateSubmissionV2(*project)
 	if err != nil {
 		return err
 	}
+	usagePackage.Warn(sub.UsageIssues(usage))
+	sub.Workspace = opts.Workspace
+	return storeAndDiffSubmissionV2(sub, compareToId, period, currency, ServerClientAddress)
+}
+
+// storeAndDiffSubmissionV2 stores the submission and shows its diff with the compareToId submission,
+// or with the latest submission of the same workspace if compareToId is empty
+func storeAndDiffSubmissionV2(sub *schema.SubmissionV2, compareToId string, period cost.Period, currency string, ServerClientAddress string) error {
 	serverClient, err := server.NewPennywiseServerClient(ServerClientAddress)
 	if err != nil {
 		return err
@@ -151,7 +216,7 @@ func terraformProjectDiff(classic bool, projectPath string, compareToId string,
 
 	var compareTo *schema.SubmissionV2
 	if compareToId == "" {
-		compareTo, err = schema.GetLatestSubmissionV2()
+		compareTo, err = schema.GetLatestSubmissionV2(sub.Workspace)
 		if err != nil {
 			return err
 		}
@@ -162,10 +227,6 @@ func terraformProjectDiff(classic bool, projectPath string, compareToId string,
 		}
 	}
 
-	sub, err := schema.CreateSubmissionV2(*project)
-	if err != nil {
-		return err
-	}
 	err = sub.StoreAsFile()
 	if err != nil {
 		return err
@@ -180,10 +241,22 @@ func terraformProjectDiff(classic bool, projectPath string, compareToId string,
 	if err != nil {
 		return err
 	}
-	err = outputDiff.ShowStateCosts(stateDiff)
+	sub.AddDiffDiagnostics(stateDiff)
+	err = showDiff(stateDiff, period, currency)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// showDiff shows the costs diff of the state for the period in the currency
+func showDiff(stateDiff *schema.ModularStateDiff, period cost.Period, currency string) error {
+	if err := stateDiff.Convert(currency); err != nil {
+		return err
+	}
+	if currency != cost.DefaultCurrency {
+		fmt.Fprintf(os.Stderr, "costs converted to %s with the exchange rates of %s\n", currency, cost.Rates.Date)
+	}
+	return outputDiff.ShowStateCosts(stateDiff, period, currency)
+}
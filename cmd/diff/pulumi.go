@@ -0,0 +1,55 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kaytu-io/pennywise/cmd/flags"
+	"github.com/kaytu-io/pennywise/pkg"
+	"github.com/kaytu-io/pennywise/pkg/parser/pulumi"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	usagePackage "github.com/kaytu-io/pennywise/pkg/usage"
+	"github.com/spf13/cobra"
+)
+
+var pulumiCommand = &cobra.Command{
+	Use:   "pulumi",
+	Short: `Shows the costs diff by parsing a Pulumi preview.`,
+	Long:  `Shows the costs diff by parsing the output of pulumi preview --json, it's compared to the latest submission of the same stack by default.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flags.ReadBooleanFlag(cmd, "classic") {
+			return fmt.Errorf("classic view not available for diff")
+		}
+		period, err := flags.ReadPeriod(cmd)
+		if err != nil {
+			return err
+		}
+		currency, err := flags.ReadCurrency(cmd)
+		if err != nil {
+			return err
+		}
+		usage, err := flags.ReadUsage(cmd)
+		if err != nil {
+			return err
+		}
+
+		preview, err := pulumi.ReadPreview(flags.ReadStringFlag(cmd, "preview-path"))
+		if err != nil {
+			return err
+		}
+		module, unsupported := preview.GetModule(usage)
+		if len(unsupported) > 0 {
+			fmt.Fprintf(os.Stderr, "resource types not supported yet: %s\n", strings.Join(unsupported, ", "))
+		}
+
+		sub, err := schema.CreateSubmissionV2(*module)
+		if err != nil {
+			return err
+		}
+		usagePackage.Warn(sub.UsageIssues(usage))
+		sub.Workspace = preview.Stack()
+		sub.Tool = &schema.IaCTool{Name: schema.PulumiTool}
+		return storeAndDiffSubmissionV2(sub, flags.ReadStringFlag(cmd, "compare-to"), period, currency, pkg.DefaultServerAddress)
+	},
+}
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"github.com/kaytu-io/pennywise/cmd/flags"
 	"github.com/kaytu-io/pennywise/pkg"
-	outputDiff "github.com/kaytu-io/pennywise/pkg/output/diff"
+	"github.com/kaytu-io/pennywise/pkg/cost"
 	"github.com/kaytu-io/pennywise/pkg/schema"
 	"github.com/kaytu-io/pennywise/pkg/server"
 	"github.com/spf13/cobra"
@@ -16,11 +16,19 @@ var submissionCommand = &cobra.Command{
 	Long:  `Shows a submission cost.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		classic := flags.ReadBooleanFlag(cmd, "classic")
+		period, err := flags.ReadPeriod(cmd)
+		if err != nil {
+			return err
+		}
+		currency, err := flags.ReadCurrency(cmd)
+		if err != nil {
+			return err
+		}
 
 		submissionId := flags.ReadStringFlag(cmd, "submission-id")
 		compareTo := flags.ReadStringFlag(cmd, "compare-to")
 
-		err := submissionsDiff(classic, submissionId, compareTo, pkg.DefaultServerAddress)
+		err = submissionsDiff(classic, period, currency, submissionId, compareTo, pkg.DefaultServerAddress)
 		if err != nil {
 			return err
 		}
@@ -29,7 +37,7 @@ var submissionCommand = &cobra.Command{
 	},
 }
 
-func submissionsDiff(classic bool, submissionId, compareToId string, ServerClientAddress string) error {
+func submissionsDiff(classic bool, period cost.Period, currency string, submissionId, compareToId string, ServerClientAddress string) error {
 	serverClient, err := server.NewPennywiseServerClient(ServerClientAddress)
 	if err != nil {
 		return err
@@ -51,10 +59,11 @@ func submissionsDiff(classic bool, submissionId, compareToId string, ServerClien
 	if err != nil {
 		return err
 	}
+	sub.AddDiffDiagnostics(stateDiff)
 	if classic {
 		return fmt.Errorf("classic view not available for diff")
 	} else {
-		err = outputDiff.ShowStateCosts(stateDiff)
+		err = showDiff(stateDiff, period, currency)
 		if err != nil {
 			return err
 		}
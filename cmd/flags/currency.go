@@ -0,0 +1,51 @@
+package flags
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kaytu-io/pennywise/pkg"
+	"github.com/kaytu-io/pennywise/pkg/cost"
+	"github.com/spf13/cobra"
+)
+
+// exchangeRatesFiles are the names of the exchange rates file looked up in ~/.pennywise if --exchange-rates is not set
+var exchangeRatesFiles = []string{"exchange-rates.yml", "exchange-rates.yaml", "exchange-rates.json"}
+
+// addCurrencyFlags adds the flags of the currency and the exchange rates read by ReadCurrency to the command
+func addCurrencyFlags(cmd *cobra.Command) {
+	cmd.Flags().String("currency", cost.DefaultCurrency, "currency the costs are shown in, ex: EUR, converted with the exchange rates")
+	cmd.Flags().String("exchange-rates", "", "exchange rates file path (date, base and rates of the currencies), defaults to ~/.pennywise/exchange-rates.yml or the rates embedded in pennywise")
+}
+
+// ReadCurrency reads the currency the costs are shown in (--currency) and sets the exchange rates the costs are
+// converted with, ex: the costs of resources priced in other currencies, from the exchange rates file
+// (--exchange-rates or ~/.pennywise/exchange-rates.yml), the rates embedded in pennywise are used if there is no file
+func ReadCurrency(cmd *cobra.Command) (string, error) {
+	currency := strings.ToUpper(ReadStringFlag(cmd, "currency"))
+	ratesPath := ReadStringOptionalFlag(cmd, "exchange-rates")
+	if ratesPath == nil {
+		if home, err := os.UserHomeDir(); err == nil {
+			for _, name := range exchangeRatesFiles {
+				path := filepath.Join(home, pkg.PennywiseDir, name)
+				if _, err := os.Stat(path); err == nil {
+					ratesPath = &path
+					break
+				}
+			}
+		}
+	}
+	if ratesPath != nil {
+		rates, err := cost.ReadExchangeRates(*ratesPath)
+		if err != nil {
+			return "", err
+		}
+		cost.Rates = rates
+	}
+	if !cost.Rates.Supports(currency) {
+		return "", fmt.Errorf("no exchange rate of %s in the exchange rates of %s, the rates can be set with --exchange-rates", currency, cost.Rates.Date)
+	}
+	return currency, nil
+}
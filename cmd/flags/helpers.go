@@ -49,6 +49,12 @@ func ReadInt64Flag(cmd *cobra.Command, name string) int64 {
 	return i
 }
 
+func ReadFloat64Flag(cmd *cobra.Command, name string) float64 {
+	str := ReadStringFlag(cmd, name)
+	f, _ := strconv.ParseFloat(str, 64)
+	return f
+}
+
 func ReadInt64OptionalFlag(cmd *cobra.Command, name string) *int64 {
 	str := ReadStringOptionalFlag(cmd, name)
 	if str != nil {
@@ -159,6 +165,23 @@ func ReadMapStringArrayFlag(cmd *cobra.Command, name string) map[string][]string
 	return nil
 }
 
+// ReadKeyValueArrayFlag reads a string array flag of KEY=VALUE items as a map
+func ReadKeyValueArrayFlag(cmd *cobra.Command, name string) (map[string]string, error) {
+	items := ReadStringArrayFlag(cmd, name)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]string, len(items))
+	for _, item := range items {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid value %q for --%s, expected KEY=VALUE", item, name)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
 func Name(n string) string {
 	return strings.ReplaceAll(strcase.ToSnake(n), "_", "-")
 }
@@ -0,0 +1,23 @@
+package flags
+
+import (
+	"github.com/kaytu-io/pennywise/pkg/cost"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+// AddOutputFlags adds the flags of the view of the costs, of the period read by ReadPeriod and of the currency read by
+// ReadCurrency to the command
+func AddOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("classic", false, "Show results in classic view (not interactive)")
+	cmd.Flags().String("period", cost.Monthly, "period the costs are shown for (hourly, daily, monthly or yearly), shown with the annualized costs")
+	cmd.Flags().Float64("hours-per-month", cost.HoursPerMonth.InexactFloat64(), "number of hours in a month of the hourly priced costs, ex: 720 for months of 30 days")
+	addCurrencyFlags(cmd)
+}
+
+// ReadPeriod reads the period the costs are shown for (--period) with the number of hours in its months
+// (--hours-per-month)
+func ReadPeriod(cmd *cobra.Command) (cost.Period, error) {
+	hours := decimal.NewFromFloat(ReadFloat64Flag(cmd, "hours-per-month"))
+	return cost.ParsePeriod(ReadStringFlag(cmd, "period"), hours)
+}
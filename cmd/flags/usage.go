@@ -0,0 +1,175 @@
+package flags
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kaytu-io/pennywise/pkg"
+	"github.com/kaytu-io/pennywise/pkg/usage"
+	"github.com/spf13/cobra"
+)
+
+// orgUsageFiles are the names of the organization usage file looked up in ~/.pennywise if --org-usage is not set
+var orgUsageFiles = []string{"usage.yml", "usage.yaml", "usage.json"}
+
+// UsageScenario is the usage of a usage profile, estimated side by side with the other profiles with --scenarios
+type UsageScenario struct {
+	Profile string
+	Usage   usage.Usage
+}
+
+// namedFile is a usage file with the name of its layer
+type namedFile struct {
+	name string
+	file usage.File
+}
+
+// AddUsageFlags adds the flags of the usage files and values read by ReadUsageLayers to the command
+func AddUsageFlags(cmd *cobra.Command) {
+	cmd.Flags().String("usage", "", "usage file path")
+	cmd.Flags().String("org-usage", "", "organization usage file path, applied before the usage file, defaults to ~/.pennywise/usage.yml")
+	cmd.Flags().StringSlice("usage-set", []string{}, "usage values (key.parameter=value) overriding the usage files, ex: aws_instance.web.monthly_hrs=100")
+	cmd.Flags().String("usage-profile", "", "usage profile of the usage files applied over their usage, ex: peak")
+}
+
+// AddScenariosFlag adds the flag of the costs estimated with the usage scenarios read by ReadUsageScenarios
+func AddScenariosFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("scenarios", false, "estimate the costs with every usage profile of the usage files and show them side by side")
+}
+
+// ReadUsageLayers reads the usage layers from the flags, in their order of precedence: the default usage, the
+// organization usage file (--org-usage or ~/.pennywise/usage.yml), the project usage file (--usage) and the
+// values of --usage-set. The usage of the --usage-profile profile of a file is applied over the file.
+func ReadUsageLayers(cmd *cobra.Command) ([]usage.Layer, error) {
+	files, err := readUsageFiles(cmd)
+	if err != nil {
+		return nil, err
+	}
+	set, err := readUsageSet(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return usageLayers(files, ReadStringFlag(cmd, "usage-profile"), set)
+}
+
+// ReadUsage reads the usage of the resources from the flags, see ReadUsageLayers
+func ReadUsage(cmd *cobra.Command) (usage.Usage, error) {
+	layers, err := ReadUsageLayers(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return usage.Layered(layers), nil
+}
+
+// ReadUsageScenarios reads the usage of the resources from the flags, see ReadUsage, and the usage of every profile
+// of the usage files, in the order of the profiles in the organization and project usage files. The usage files are
+// read once for both.
+func ReadUsageScenarios(cmd *cobra.Command) (usage.Usage, []UsageScenario, error) {
+	files, err := readUsageFiles(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	set, err := readUsageSet(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	layers, err := usageLayers(files, ReadStringFlag(cmd, "usage-profile"), set)
+	if err != nil {
+		return nil, nil, err
+	}
+	var scenarios []UsageScenario
+	seen := make(map[string]bool)
+	for _, f := range files {
+		for _, p := range f.file.Profiles {
+			if seen[p.Name] {
+				continue
+			}
+			seen[p.Name] = true
+			profileLayers, err := usageLayers(files, p.Name, set)
+			if err != nil {
+				return nil, nil, err
+			}
+			scenarios = append(scenarios, UsageScenario{Profile: p.Name, Usage: usage.Layered(profileLayers)})
+		}
+	}
+	if len(scenarios) == 0 {
+		return nil, nil, fmt.Errorf("the usage files have no usage profiles")
+	}
+	return usage.Layered(layers), scenarios, nil
+}
+
+// usageLayers returns the layers of the default usage, the usage files with their profile and the usage set
+func usageLayers(files []namedFile, profile string, set usage.Usage) ([]usage.Layer, error) {
+	layers := []usage.Layer{{Name: "default", Usage: usage.Default}}
+	found := false
+	for _, f := range files {
+		layers = append(layers, usage.Layer{Name: f.name, Usage: f.file.Usage})
+		if profile == "" {
+			continue
+		}
+		if u, ok := f.file.Profile(profile); ok {
+			found = true
+			layers = append(layers, usage.Layer{Name: fmt.Sprintf("%s/%s", f.name, profile), Usage: u})
+		}
+	}
+	if profile != "" && !found {
+		return nil, fmt.Errorf("usage profile %s not found in the usage files", profile)
+	}
+	if set != nil {
+		layers = append(layers, usage.Layer{Name: "usage-set", Usage: set})
+	}
+	return layers, nil
+}
+
+// readUsageFiles reads the organization usage file (--org-usage or ~/.pennywise/usage.yml) and the project
+// usage file (--usage)
+func readUsageFiles(cmd *cobra.Command) ([]namedFile, error) {
+	var files []namedFile
+
+	orgPath := ReadStringOptionalFlag(cmd, "org-usage")
+	if orgPath == nil {
+		if home, err := os.UserHomeDir(); err == nil {
+			for _, name := range orgUsageFiles {
+				path := filepath.Join(home, pkg.PennywiseDir, name)
+				if _, err := os.Stat(path); err == nil {
+					orgPath = &path
+					break
+				}
+			}
+		}
+	}
+	if orgPath != nil {
+		f, err := usage.ReadFile(*orgPath)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, namedFile{name: "organization", file: f})
+	}
+
+	if usagePath := ReadStringOptionalFlag(cmd, "usage"); usagePath != nil {
+		f, err := usage.ReadFile(*usagePath)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, namedFile{name: "project", file: f})
+	}
+	return files, nil
+}
+
+// readUsageSet reads the values of --usage-set, nil if there are none
+func readUsageSet(cmd *cobra.Command) (usage.Usage, error) {
+	values, err := ReadKeyValueArrayFlag(cmd, "usage-set")
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	u, err := usage.ParseSet(values)
+	if err != nil {
+		return nil, err
+	}
+	usage.Warn(u.Validate())
+	return u, nil
+}
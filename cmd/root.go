@@ -6,13 +6,25 @@ import (
 	"github.com/kaytu-io/pennywise/cmd/diff"
 	"github.com/kaytu-io/pennywise/cmd/optimize"
 	"github.com/kaytu-io/pennywise/cmd/predef"
+	"github.com/kaytu-io/pennywise/cmd/usage"
+	"github.com/kaytu-io/pennywise/pkg"
+	"github.com/kaytu-io/pennywise/pkg/parser/registry"
 	"github.com/spf13/cobra"
 	"os"
+	"path/filepath"
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use: "pennywise",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The providers that are not built in are defined by the manifests of ~/.pennywise/providers
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		return registry.LoadManifests(filepath.Join(home, pkg.PennywiseDir, "providers"))
+	},
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		if cmd.Flags().ParseErrorsWhitelist.UnknownFlags {
 			return errors.New("invalid flags")
@@ -25,6 +37,7 @@ func init() {
 	//rootCmd.AddCommand(ingestion.IngestCmd)
 	rootCmd.AddCommand(cost.CostCmd)
 	rootCmd.AddCommand(diff.DiffCmd)
+	rootCmd.AddCommand(usage.UsageCmd)
 	rootCmd.AddCommand(optimize.OptimizeCmd)
 
 	rootCmd.AddCommand(predef.VersionCmd)
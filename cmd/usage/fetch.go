@@ -0,0 +1,109 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kaytu-io/pennywise/cmd/flags"
+	awsConfig "github.com/kaytu-io/pennywise/pkg/aws"
+	"github.com/kaytu-io/pennywise/pkg/plan"
+	usagePackage "github.com/kaytu-io/pennywise/pkg/usage"
+	"github.com/kaytu-io/pennywise/pkg/usage/fetch"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+var fetchCommand = &cobra.Command{
+	Use:   "fetch",
+	Short: `Writes a usage file with the usage of the deployed resources fetched from CloudWatch.`,
+	Long: `Writes a usage file with the usage of the AWS resources of the terraform state (or of the prior state of the plan)
+fetched from their CloudWatch metrics over the lookback window, ex: the requests of the lambda functions or the data
+processed by the NAT gateways. The values of the existing usage file are kept unless they are fetched.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lookbackDays := int(flags.ReadInt64Flag(cmd, "lookback-days"))
+		if lookbackDays < 1 {
+			return fmt.Errorf("invalid lookback days %d, it must be at least 1", lookbackDays)
+		}
+		usagePath := flags.ReadStringFlag(cmd, "usage")
+		if err := usagePackage.CheckTemplatePath(usagePath); err != nil {
+			return err
+		}
+
+		var state []byte
+		var err error
+		if planPath := flags.ReadStringOptionalFlag(cmd, "plan-path"); planPath != nil {
+			state, err = plan.Read(*planPath, plan.Options{
+				Dir:    flags.ReadStringFlag(cmd, "project-path"),
+				Binary: flags.ReadStringFlag(cmd, "terraform-binary"),
+			})
+		} else {
+			var binary string
+			binary, err = plan.FindBinary(flags.ReadStringFlag(cmd, "terraform-binary"))
+			if err != nil {
+				return err
+			}
+			state, err = plan.ShowState(binary, flags.ReadStringFlag(cmd, "project-path"))
+		}
+		if err != nil {
+			return err
+		}
+		resources, err := fetch.StateResources(state)
+		if err != nil {
+			return err
+		}
+
+		var file usagePackage.File
+		if _, err := os.Stat(usagePath); err == nil {
+			file, err = usagePackage.ReadFile(usagePath)
+			if err != nil {
+				return err
+			}
+		}
+		if file.Usage == nil {
+			file.Usage = make(usagePackage.Usage)
+		}
+
+		ctx := context.Background()
+		profile := flags.ReadStringFlag(cmd, "profile")
+		cfg, err := awsConfig.GetConfig(ctx, "", "", "", "", &profile, nil)
+		if err != nil {
+			return err
+		}
+		if region := flags.ReadStringFlag(cmd, "region"); region != "" {
+			cfg.Region = region
+		}
+		fetcher := fetch.NewFetcher(cfg, time.Duration(lookbackDays)*24*time.Hour)
+
+		types := make(map[string]string)
+		fetched := 0
+		for _, res := range resources {
+			types[res.Address] = res.Type
+			if !fetch.Supported(res.Type) {
+				continue
+			}
+			values, err := fetcher.Fetch(ctx, res)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to fetch the usage of %s: %s\n", res.Address, err)
+				continue
+			}
+			if len(values) == 0 {
+				continue
+			}
+			if file.Usage[res.Address] == nil {
+				file.Usage[res.Address] = make(map[string]interface{})
+			}
+			for name, v := range values {
+				file.Usage[res.Address][name] = v
+			}
+			fetched++
+		}
+
+		err = os.WriteFile(usagePath, usagePackage.Template(types, file), 0644)
+		if err != nil {
+			return fmt.Errorf("failed to write usage file: %w", err)
+		}
+		fmt.Printf("Usage of %d resources fetched from the last %d days of CloudWatch metrics, written to %s\n", fetched, lookbackDays, usagePath)
+		return nil
+	},
+}
@@ -0,0 +1,85 @@
+package usage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/kaytu-io/pennywise/cmd/cost/terraform"
+	"github.com/kaytu-io/pennywise/cmd/flags"
+	"github.com/kaytu-io/pennywise/pkg/parser/hcl"
+	"github.com/kaytu-io/pennywise/pkg/plan"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	usagePackage "github.com/kaytu-io/pennywise/pkg/usage"
+	"github.com/spf13/cobra"
+)
+
+var initCommand = &cobra.Command{
+	Use:   "init",
+	Short: `Writes a usage file template with the usage parameters of the project resources.`,
+	Long: `Writes a usage file template (yaml) with the usage parameters of the project resources that have usage-based costs,
+commented with their units and default values. The values of the existing usage file are kept.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		usagePath := flags.ReadStringFlag(cmd, "usage")
+		if err := usagePackage.CheckTemplatePath(usagePath); err != nil {
+			return err
+		}
+		var existing usagePackage.File
+		if _, err := os.Stat(usagePath); err == nil {
+			existing, err = usagePackage.ReadFile(usagePath)
+			if err != nil {
+				return err
+			}
+		}
+
+		resources, err := projectResources(cmd)
+		if err != nil {
+			return err
+		}
+		types := make(map[string]string)
+		for _, res := range resources {
+			// The usage of an address without index is used for all the instances of the resource
+			types[usagePackage.TrimIndexes(res.Address)] = res.Type
+		}
+
+		err = os.WriteFile(usagePath, usagePackage.Template(types, existing), 0644)
+		if err != nil {
+			return fmt.Errorf("failed to write usage file: %w", err)
+		}
+		fmt.Printf("Usage file written to %s\n", usagePath)
+		return nil
+	},
+}
+
+// projectResources returns the resources of the plan, if a plan path is given, or of the terraform project
+func projectResources(cmd *cobra.Command) ([]schema.ResourceDef, error) {
+	projectPath := flags.ReadStringFlag(cmd, "project-path")
+	workspace := flags.ReadStringFlag(cmd, "workspace")
+	if workspace == "" {
+		workspace = schema.GetProjectWorkspace(projectPath)
+	}
+	env, err := flags.ReadKeyValueArrayFlag(cmd, "env")
+	if err != nil {
+		return nil, err
+	}
+	tfVarFiles := flags.ReadStringArrayFlag(cmd, "terraform-var-file")
+
+	if planPath := flags.ReadStringOptionalFlag(cmd, "plan-path"); planPath != nil {
+		planJson, err := plan.Read(*planPath, plan.Options{
+			Dir:    projectPath,
+			Binary: flags.ReadStringFlag(cmd, "terraform-binary"),
+		})
+		if err != nil {
+			return nil, err
+		}
+		resources, _, err := terraform.ParseTerraformPlanJson(bytes.NewReader(planJson), usagePackage.Usage{})
+		return resources, err
+	}
+
+	module, err := hcl.ParseHclResources(projectPath, usagePackage.Usage{}, hcl.Options{VarFiles: tfVarFiles, Env: env, Workspace: workspace})
+	if err != nil {
+		return nil, err
+	}
+	sub := schema.SubmissionV2{RootModule: *module}
+	return sub.GetResources(), nil
+}
@@ -0,0 +1,47 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kaytu-io/pennywise/cmd/flags"
+	usagePackage "github.com/kaytu-io/pennywise/pkg/usage"
+	"github.com/spf13/cobra"
+)
+
+var showCommand = &cobra.Command{
+	Use:   "show",
+	Short: `Shows the effective usage of the project resources.`,
+	Long: `Shows the effective usage of every resource of the project, from the default usage, the organization usage file,
+the project usage file and the --usage-set values, with the layer and the usage key each value comes from.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		layers, err := flags.ReadUsageLayers(cmd)
+		if err != nil {
+			return err
+		}
+		usage := usagePackage.Layered(layers)
+
+		resources, err := projectResources(cmd)
+		if err != nil {
+			return err
+		}
+		sort.Slice(resources, func(i, j int) bool { return resources[i].Address < resources[j].Address })
+		for _, res := range resources {
+			values := usage.GetUsage(res.Type, res.GetUsageAddress())
+			if len(values) == 0 {
+				continue
+			}
+			fmt.Println(res.Address)
+			names := make([]string, 0, len(values))
+			for name := range values {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				key, layer := usage.Source(res.Type, res.GetUsageAddress(), name)
+				fmt.Printf("  %s: %v (%s, %s)\n", name, values[name], layers[layer].Name, key)
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,45 @@
+package usage
+
+import (
+	"github.com/kaytu-io/pennywise/cmd/flags"
+	"github.com/spf13/cobra"
+)
+
+// UsageCmd usage commands
+var UsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: `Manages the usage files of the projects.`,
+	Long:  `Manages the usage files which define the usage of the resources used to estimate their usage-based costs.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	UsageCmd.AddCommand(initCommand)
+	addProjectFlags(initCommand)
+	initCommand.Flags().String("usage", "pennywise-usage.yml", "usage file path (.yml or .yaml), the values of the existing file are kept")
+
+	UsageCmd.AddCommand(showCommand)
+	addProjectFlags(showCommand)
+	flags.AddUsageFlags(showCommand)
+
+	UsageCmd.AddCommand(fetchCommand)
+	fetchCommand.Flags().String("profile", "", "AWS profile for authentication")
+	fetchCommand.Flags().String("region", "", "region of the resources without arn in the state, defaults to the region of the AWS profile")
+	fetchCommand.Flags().Int("lookback-days", 30, "number of days of metrics the usage is computed from")
+	fetchCommand.Flags().String("plan-path", "", "terraform plan file path whose prior state is used, either the json or the binary file (-out of terraform plan), or terraform state json file path (terraform show -json)")
+	fetchCommand.Flags().String("project-path", ".", "path to terraform project, its state is read with terraform show if no plan is given")
+	fetchCommand.Flags().String("terraform-binary", "", "terraform or tofu binary used to read the state (looked up on PATH by default)")
+	fetchCommand.Flags().String("usage", "pennywise-usage.yml", "usage file path (.yml or .yaml), the values of the existing file are kept unless they are fetched")
+}
+
+// addProjectFlags adds the flags of the project the resources are parsed from
+func addProjectFlags(cmd *cobra.Command) {
+	cmd.Flags().String("plan-path", "", "terraform plan file path, either the json or the binary file (-out of terraform plan)")
+	cmd.Flags().String("project-path", ".", "path to terraform project")
+	cmd.Flags().StringSlice("terraform-var-file", []string{}, "path to terraform variables file")
+	cmd.Flags().StringSlice("env", []string{}, "environment variables (KEY=VALUE) used to evaluate the terraform project, ex: TF_VAR_region=us-east-1")
+	cmd.Flags().String("workspace", "", "terraform workspace used to evaluate the project, defaults to the workspace of the project in the projects config")
+	cmd.Flags().String("terraform-binary", "", "terraform or tofu binary used to convert a binary plan file (looked up on PATH by default)")
+}
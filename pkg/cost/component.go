@@ -35,10 +35,16 @@ func (c Component) GetRounded() Component {
 
 // Cost returns the cost of this component (Rate multiplied by Quantity).
 func (c Component) Cost() Cost {
+	return c.PeriodCost(Month)
+}
+
+// PeriodCost returns the cost of this component for the period, the monthly quantities are priced by months and the
+// hourly quantities by hours of the period.
+func (c Component) PeriodCost(p Period) Cost {
 	if !c.MonthlyQuantity.IsZero() {
-		return c.Rate.MulDecimal(c.MonthlyQuantity)
+		return c.Rate.MulDecimal(c.MonthlyQuantity.Mul(p.months()))
 	} else if !c.HourlyQuantity.IsZero() {
-		return c.Rate.MulDecimal(c.HourlyQuantity.Mul(HoursPerMonth))
+		return c.Rate.MulDecimal(c.HourlyQuantity.Mul(p.hours()))
 	} else {
 		return Zero
 	}
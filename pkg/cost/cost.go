@@ -32,7 +32,7 @@ func (c Cost) Hourly() decimal.Decimal {
 }
 
 // Add adds the values of two Cost structs.
-// If the currency of both costs doesn't match, error is returned.
+// If the currency of both costs doesn't match, the added cost is converted to the currency of the cost with Rates.
 func (c Cost) Add(c2 Cost) (Cost, error) {
 	// if cost addition iz Zero, ignore it
 	if c2 == Zero {
@@ -45,10 +45,14 @@ func (c Cost) Add(c2 Cost) (Cost, error) {
 	}
 
 	if c.Currency != c2.Currency {
-		return Zero, fmt.Errorf("currency mismatch: expected %s, got %s", c.Currency, c2.Currency)
+		converted, err := c2.Convert(c.Currency)
+		if err != nil {
+			return Zero, fmt.Errorf("currency mismatch: failed to convert %s to %s: %w", c2.Currency, c.Currency, err)
+		}
+		c2 = converted
 	}
 
-	return Cost{Decimal: c.Decimal.Add(c2.Monthly()), Currency: c.Currency}, nil
+	return Cost{Decimal: c.Decimal.Add(c2.Decimal), Currency: c.Currency}, nil
 }
 
 // MulDecimal multiplies the Cost by the given decimal.Decimal.
@@ -0,0 +1,178 @@
+package cost
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leekchan/accounting"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultCurrency is the currency of the prices
+const DefaultCurrency = "USD"
+
+// defaultRatesContent are the exchange rates used if no exchange rates file is given, they are the rates of their date
+// and should be updated with a file of recent rates for accurate conversions.
+//
+//go:embed exchange-rates.json
+var defaultRatesContent []byte
+
+// ExchangeRates are the exchange rates of the currencies at a date, as the amount of every currency for one unit
+// of the base currency
+type ExchangeRates struct {
+	// Date is the date of the rates, ex: 2024-10-01
+	Date  string             `json:"date" yaml:"date"`
+	Base  string             `json:"base" yaml:"base"`
+	Rates map[string]float64 `json:"rates" yaml:"rates"`
+}
+
+// Rates are the exchange rates the costs are converted with, ex: to add the costs of different currencies
+var Rates ExchangeRates
+
+func init() {
+	if err := json.Unmarshal(defaultRatesContent, &Rates); err != nil {
+		panic(err)
+	}
+}
+
+// ReadExchangeRates reads the exchange rates file on the path, the file can be in json or yaml format
+func ReadExchangeRates(path string) (ExchangeRates, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ExchangeRates{}, fmt.Errorf("failed to read exchange rates file: %w", err)
+	}
+	var rates ExchangeRates
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		err = json.Unmarshal(content, &rates)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &rates)
+	default:
+		return ExchangeRates{}, fmt.Errorf("unsupported file format %s for exchange rates file", ext)
+	}
+	if err != nil {
+		return ExchangeRates{}, fmt.Errorf("failed to parse exchange rates file: %w", err)
+	}
+	if rates.Date == "" {
+		return ExchangeRates{}, fmt.Errorf("exchange rates file %s has no date", path)
+	}
+	if rates.Base == "" {
+		return ExchangeRates{}, fmt.Errorf("exchange rates file %s has no base currency", path)
+	}
+	rates.Base = strings.ToUpper(rates.Base)
+	for currency, rate := range rates.Rates {
+		if rate <= 0 {
+			return ExchangeRates{}, fmt.Errorf("exchange rate of %s must be positive, got %v", currency, rate)
+		}
+	}
+	return rates, nil
+}
+
+// Supports returns true if the costs can be converted to the currency with the rates
+func (r ExchangeRates) Supports(currency string) bool {
+	_, ok := r.rate(currency)
+	return ok
+}
+
+// Rate returns the exchange rate to convert an amount of the from currency to the to currency
+func (r ExchangeRates) Rate(from, to string) (decimal.Decimal, error) {
+	fromRate, ok := r.rate(from)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("no exchange rate of %s in the exchange rates of %s", from, r.Date)
+	}
+	toRate, ok := r.rate(to)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("no exchange rate of %s in the exchange rates of %s", to, r.Date)
+	}
+	return toRate.DivRound(fromRate, 8), nil
+}
+
+// rate returns the amount of the currency for one unit of the base currency
+func (r ExchangeRates) rate(currency string) (decimal.Decimal, bool) {
+	if currency == r.Base {
+		return decimal.NewFromInt(1), true
+	}
+	for c, rate := range r.Rates {
+		if strings.EqualFold(c, currency) {
+			return decimal.NewFromFloat(rate), true
+		}
+	}
+	return decimal.Zero, false
+}
+
+// Convert returns the cost converted to the currency with Rates, the costs without currency are in DefaultCurrency
+func (c Cost) Convert(currency string) (Cost, error) {
+	from := c.Currency
+	if from == "" {
+		from = DefaultCurrency
+	}
+	if from == currency {
+		return Cost{Decimal: c.Decimal, Currency: currency}, nil
+	}
+	rate, err := Rates.Rate(from, currency)
+	if err != nil {
+		return Zero, err
+	}
+	return Cost{Decimal: c.Decimal.Mul(rate), Currency: currency}, nil
+}
+
+// Convert converts the rates of the components of the resources of the state and its child modules to the currency
+func (s *ModularState) Convert(currency string) error {
+	for name, res := range s.Resources {
+		if err := res.Convert(currency); err != nil {
+			return fmt.Errorf("failed to convert the costs of resource %s: %w", name, err)
+		}
+	}
+	for _, child := range s.ChildModules {
+		if err := child.Convert(currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Convert converts the rates of the components of the resource to the currency
+func (re Resource) Convert(currency string) error {
+	for _, comps := range re.Components {
+		for i := range comps {
+			rate, err := comps[i].Rate.Convert(currency)
+			if err != nil {
+				return err
+			}
+			comps[i].Rate = rate
+		}
+	}
+	return nil
+}
+
+// Accounting returns the accounting formatting the money values of the period in the currency with its symbol and
+// separators, ex: €1.234,56 for EUR per month
+func Accounting(currency string, period Period) *accounting.Accounting {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	locale, ok := accounting.LocaleInfo[currency]
+	if !ok || locale.ComSymbol == "" {
+		return &accounting.Accounting{Symbol: currency, Precision: period.Precision(2), Format: "%v %s"}
+	}
+	var space string
+	if locale.SpaceSep == " " {
+		space = " "
+	}
+	format := "%s" + space + "%v"
+	if !locale.Pre {
+		format = "%v" + space + "%s"
+	}
+	return &accounting.Accounting{
+		Symbol:    locale.ComSymbol,
+		Precision: period.Precision(locale.FractionLength),
+		Thousand:  locale.ThouSep,
+		Decimal:   locale.DecSep,
+		Format:    format,
+	}
+}
@@ -0,0 +1,119 @@
+package cost
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	Hourly  = "hourly"
+	Daily   = "daily"
+	Monthly = "monthly"
+	Yearly  = "yearly"
+)
+
+// Periods are the names of the periods the costs can be shown for
+var Periods = []string{Hourly, Daily, Monthly, Yearly}
+
+// hoursPerYear is the number of hours in a year of 365 days
+var hoursPerYear = decimal.NewFromInt(8760)
+
+// Period is the period the costs are shown for
+type Period struct {
+	// Name of the period, ex: monthly
+	Name string
+	// HoursPerMonth is the number of hours in a month the hourly priced components are costed for, ex: 720 for
+	// months of 30 days
+	HoursPerMonth decimal.Decimal
+}
+
+// Month is the monthly period of HoursPerMonth hours the costs are priced for
+var Month = Period{Name: Monthly, HoursPerMonth: HoursPerMonth}
+
+// ParsePeriod returns the period of the name, ex: monthly, with months of hoursPerMonth hours
+func ParsePeriod(name string, hoursPerMonth decimal.Decimal) (Period, error) {
+	if !hoursPerMonth.IsPositive() {
+		return Period{}, fmt.Errorf("hours per month must be positive, got %s", hoursPerMonth)
+	}
+	for _, p := range Periods {
+		if p == strings.ToLower(name) {
+			return Period{Name: p, HoursPerMonth: hoursPerMonth}, nil
+		}
+	}
+	return Period{}, fmt.Errorf("unknown period %q, expected one of hourly, daily, monthly or yearly", name)
+}
+
+// Annual returns the yearly period of the period, the annualized costs are shown with the costs of the other periods
+func (p Period) Annual() Period {
+	return Period{Name: Yearly, HoursPerMonth: p.HoursPerMonth}
+}
+
+// hours returns the number of hours of the period, the years are of 8760 hours whatever the hours of the months
+func (p Period) hours() decimal.Decimal {
+	switch p.Name {
+	case Hourly:
+		return decimal.NewFromInt(1)
+	case Daily:
+		return decimal.NewFromInt(24)
+	case Yearly:
+		return hoursPerYear
+	default:
+		if p.HoursPerMonth.IsZero() {
+			return HoursPerMonth
+		}
+		return p.HoursPerMonth
+	}
+}
+
+// months returns the number of months of the period, the shorter periods are the part of a month of HoursPerMonth
+// hours they last
+func (p Period) months() decimal.Decimal {
+	switch p.Name {
+	case Hourly, Daily:
+		return p.hours().Div(HoursPerMonth)
+	case Yearly:
+		return decimal.NewFromInt(12)
+	default:
+		return decimal.NewFromInt(1)
+	}
+}
+
+// Precision returns the number of decimals the costs of the period are shown with, at least the decimals of the
+// currency: the costs per hour or per day are mostly fractions of cents, ex: $0.0104 per hour
+func (p Period) Precision(decimals int) int {
+	var precision int
+	switch p.Name {
+	case Hourly:
+		precision = 6
+	case Daily:
+		precision = 4
+	}
+	if precision < decimals {
+		return decimals
+	}
+	return precision
+}
+
+// Title returns the name of the period in titles, ex: Monthly for Monthly Cost
+func (p Period) Title() string {
+	if p.Name == "" {
+		return Month.Title()
+	}
+	return strings.ToUpper(p.Name[:1]) + p.Name[1:]
+}
+
+// Label returns the period in labels, ex: per month for Total Cost (per month)
+func (p Period) Label() string {
+	switch p.Name {
+	case Hourly:
+		return "per hour"
+	case Daily:
+		return "per day"
+	case Yearly:
+		return "per year"
+	default:
+		return "per month"
+	}
+}
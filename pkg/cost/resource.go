@@ -3,6 +3,7 @@ package cost
 import (
 	"fmt"
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/kaytu-io/pennywise/pkg/diagnostic"
 )
 
 // Resource represents costs of a single cloud resource. Each Resource includes a Component map, keyed
@@ -14,16 +15,24 @@ type Resource struct {
 	Components  map[string][]Component
 	Skipped     bool
 	IsSupported bool
+	// Diagnostics are the issues found while parsing the resource, its cost may be inaccurate if there are any
+	Diagnostics []diagnostic.Diagnostic
 }
 
 // Cost returns the sum of costs of every Component of this Resource.
-// Error is returned if there is a mismatch in Component currency.
+// Error is returned if the currency of a Component can't be converted.
 func (re Resource) Cost() (Cost, error) {
+	return re.PeriodCost(Month)
+}
+
+// PeriodCost returns the sum of costs for the period of every Component of this Resource.
+// Error is returned if the currency of a Component can't be converted.
+func (re Resource) PeriodCost(period Period) (Cost, error) {
 	var total Cost
 	var err error
 	for name, comp := range re.Components {
 		for _, c := range comp {
-			total, err = total.Add(c.Cost())
+			total, err = total.Add(c.PeriodCost(period))
 			if err != nil {
 				return Zero, fmt.Errorf("failed to add cost of component %s: %w", name, err)
 			}
@@ -34,16 +43,19 @@ func (re Resource) Cost() (Cost, error) {
 }
 
 // CostRows returns rows for resource components
-// containing the components costs and total cost for the resource
-func (re Resource) CostRows() ([]table.Row, error) {
+// containing the components costs for the period and total cost for the resource
+func (re Resource) CostRows(period Period) ([]table.Row, error) {
 	var rows []table.Row
 
 	for _, comps := range re.Components {
 		for _, c := range comps {
 			var row table.Row
-			row = append(row, faint.Sprint("└─ ")+c.Name, c.Rate.Decimal, c.HourlyQuantity, c.MonthlyQuantity, c.Unit, c.Cost().Decimal)
+			row = append(row, faint.Sprint("└─ ")+c.Name, c.Rate.Decimal, c.HourlyQuantity, c.MonthlyQuantity, c.Unit, c.PeriodCost(period).Decimal.Round(int32(period.Precision(2))))
 			rows = append(rows, row)
 		}
 	}
+	for _, d := range re.Diagnostics {
+		rows = append(rows, table.Row{faint.Sprint("└─ ⚠ " + d.String())})
+	}
 	return rows, nil
 }
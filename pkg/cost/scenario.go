@@ -0,0 +1,110 @@
+package cost
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// Scenario is the state of the costs estimated with the usage of a usage profile, ex: peak
+type Scenario struct {
+	Name  string
+	State *ModularState
+}
+
+// ScenariosString returns a string to show the costs of the scenarios side by side: the cost for the period of every
+// resource and child module of the root module with a column per scenario, and the total and annualized cost of
+// every scenario
+func ScenariosString(scenarios []Scenario, period Period) (string, error) {
+	t := table.NewWriter()
+	t.Style().Options.DrawBorder = false
+	t.Style().Options.SeparateColumns = false
+	t.Style().Options.SeparateRows = false
+	t.Style().Options.SeparateHeader = false
+	t.Style().Format.Header = text.FormatDefault
+	t.Style().Format.Footer = text.FormatDefault
+
+	headers := table.Row{underline.Sprint("Name")}
+	columns := []table.ColumnConfig{{Number: 1, Align: text.AlignLeft, AlignHeader: text.AlignLeft}}
+	for i, s := range scenarios {
+		headers = append(headers, underline.Sprint(s.Name))
+		columns = append(columns, table.ColumnConfig{Number: i + 2, Align: text.AlignRight, AlignHeader: text.AlignRight, AlignFooter: text.AlignRight})
+	}
+	t.AppendHeader(headers)
+	t.SetColumnConfigs(columns)
+
+	// costs are the costs of the resources and child modules by scenario
+	costs := make(map[string][]*Cost)
+	modules := make(map[string]bool)
+	for i, s := range scenarios {
+		for name, res := range s.State.Resources {
+			if !res.IsSupported {
+				continue
+			}
+			c, err := res.PeriodCost(period)
+			if err != nil {
+				return "", fmt.Errorf("failed to get cost of resource %s: %w", name, err)
+			}
+			setScenarioCost(costs, name, i, len(scenarios), c)
+		}
+		for name, child := range s.State.ChildModules {
+			c, err := child.PeriodCost(period)
+			if err != nil {
+				return "", fmt.Errorf("failed to get cost of module %s: %w", name, err)
+			}
+			modules[name] = true
+			setScenarioCost(costs, name, i, len(scenarios), c)
+		}
+	}
+
+	names := make([]string, 0, len(costs))
+	for name := range costs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		label := name
+		if modules[name] {
+			label = bold.Sprint(name)
+		}
+		row := table.Row{label}
+		for _, c := range costs[name] {
+			if c == nil {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, c.Decimal.Round(int32(period.Precision(2))))
+		}
+		t.AppendRow(row)
+	}
+
+	footer := table.Row{bold.Sprint(fmt.Sprintf("Total Cost (%s)", period.Label()))}
+	annualized := table.Row{bold.Sprint("Annualized Cost (per year)")}
+	for _, s := range scenarios {
+		total, err := s.State.PeriodCost(period)
+		if err != nil {
+			return "", err
+		}
+		annualTotal, err := s.State.PeriodCost(period.Annual())
+		if err != nil {
+			return "", err
+		}
+		footer = append(footer, bold.Sprint(Accounting(total.Currency, period).FormatMoney(total.Decimal)))
+		annualized = append(annualized, bold.Sprint(Accounting(total.Currency, period.Annual()).FormatMoney(annualTotal.Decimal)))
+	}
+	t.AppendFooter(footer)
+	if period.Name != Yearly {
+		t.AppendFooter(annualized)
+	}
+	return t.Render(), nil
+}
+
+// setScenarioCost sets the cost of the resource or module of the scenario
+func setScenarioCost(costs map[string][]*Cost, name string, scenario, scenarios int, c Cost) {
+	if costs[name] == nil {
+		costs[name] = make([]*Cost, scenarios)
+	}
+	costs[name][scenario] = &c
+}
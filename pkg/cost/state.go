@@ -39,6 +39,20 @@ func getModuleResources(state ModularState) map[string]Resource {
 	return resources
 }
 
+// DiagnosticResourcesCount returns the number of resources of the module and its child modules that have diagnostics
+func (s *ModularState) DiagnosticResourcesCount() int {
+	var count int
+	for _, res := range s.Resources {
+		if len(res.Diagnostics) > 0 {
+			count++
+		}
+	}
+	for _, child := range s.ChildModules {
+		count += child.DiagnosticResourcesCount()
+	}
+	return count
+}
+
 func (s *ModularState) TotalResourcesCount() int {
 	return resourcesCount(*s)
 }
@@ -69,11 +83,21 @@ var underline = color.New(color.Underline)
 var primaryLink = color.New(color.Underline).Add(color.Bold)
 
 // Cost returns the sum of the costs of every Resource included in this State.
-// Error is returned if there is a mismatch in resource currencies.
+// Error is returned if the currency of a resource can't be converted.
 func (s *State) Cost() (Cost, error) {
+	total, err := s.PeriodCost(Month)
+	if err != nil {
+		return Zero, err
+	}
+	return Cost{Currency: total.Currency, Decimal: total.Decimal.Round(3)}, nil
+}
+
+// PeriodCost returns the sum of the costs for the period of every Resource included in this State.
+// Error is returned if the currency of a resource can't be converted.
+func (s *State) PeriodCost(period Period) (Cost, error) {
 	var total Cost
 	for name, re := range s.Resources {
-		rCost, err := re.Cost()
+		rCost, err := re.PeriodCost(period)
 		if err != nil {
 			return Zero, fmt.Errorf("failed to get cost of resource %s: %w", name, err)
 		}
@@ -83,17 +107,22 @@ func (s *State) Cost() (Cost, error) {
 		}
 	}
 
-	return Cost{Currency: total.Currency, Decimal: total.Decimal.Round(3)}, nil
+	return total, nil
 }
 
 func (s *ModularState) Cost() (Cost, error) {
-	return moduleCost(*s)
+	return moduleCost(*s, Month)
 }
 
-func moduleCost(state ModularState) (Cost, error) {
+// PeriodCost returns the sum of the costs for the period of the resources of the state and its child modules
+func (s *ModularState) PeriodCost(period Period) (Cost, error) {
+	return moduleCost(*s, period)
+}
+
+func moduleCost(state ModularState, period Period) (Cost, error) {
 	var total Cost
 	for name, re := range state.Resources {
-		rCost, err := re.Cost()
+		rCost, err := re.PeriodCost(period)
 		if err != nil {
 			return Zero, fmt.Errorf("failed to get cost of resource %s: %w", name, err)
 		}
@@ -103,7 +132,7 @@ func moduleCost(state ModularState) (Cost, error) {
 		}
 	}
 	for name, childModule := range state.ChildModules {
-		childModuleCost, err := moduleCost(childModule)
+		childModuleCost, err := moduleCost(childModule, period)
 		if err != nil {
 			return Zero, fmt.Errorf("failed to get cost of module %s: %w", name, err)
 		}
@@ -129,7 +158,8 @@ func (s *State) GetCostComponents() []Component {
 
 // CostString returns a string to show the breakdown of the costs for a state
 // containing the resources and their components costs and total cost for the resources and the state
-func (s *State) CostString() (string, error) {
+// for the period, with the annualized total cost
+func (s *State) CostString(period Period) (string, error) {
 	var costString string
 
 	t := table.NewWriter()
@@ -180,7 +210,7 @@ func (s *State) CostString() (string, error) {
 	})
 	i++
 
-	headers = append(headers, underline.Sprint("Monthly Cost"))
+	headers = append(headers, underline.Sprint(period.Title()+" Cost"))
 	columns = append(columns, table.ColumnConfig{
 		Number:      i,
 		Align:       text.AlignRight,
@@ -194,7 +224,12 @@ func (s *State) CostString() (string, error) {
 	t.AppendHeader(headers)
 
 	var unsupportedServices []string
-	cost, err := s.Cost()
+	var diagnosticResources int
+	cost, err := s.PeriodCost(period)
+	if err != nil {
+		return "", err
+	}
+	annualCost, err := s.PeriodCost(period.Annual())
 	if err != nil {
 		return "", err
 	}
@@ -205,13 +240,16 @@ func (s *State) CostString() (string, error) {
 			unsupportedServices = append(unsupportedServices, rs.Type)
 			continue
 		}
-		cost, err := rs.Cost()
+		cost, err := rs.PeriodCost(period)
 		if err != nil {
 			return "", err
 		}
+		if len(rs.Diagnostics) > 0 {
+			diagnosticResources++
+		}
 		var row table.Row
-		row = append(row, bold.Sprint(rs.Address), "", "", "", "", cost.Decimal.Round(2))
-		costRows, err := rs.CostRows()
+		row = append(row, bold.Sprint(rs.Address), "", "", "", "", cost.Decimal.Round(int32(period.Precision(2))))
+		costRows, err := rs.CostRows(period)
 		if err != nil {
 			return "", err
 		}
@@ -223,7 +261,11 @@ func (s *State) CostString() (string, error) {
 
 	costString = t.Render()
 	costString += "\n──────────────────────────────────\n"
-	costString += fmt.Sprintf("%s:    %v", bold.Sprint("Total Cost (per month)"), cost.Decimal.Round(2))
+	ac := Accounting(cost.Currency, period)
+	costString += fmt.Sprintf("%s:    %v", bold.Sprint(fmt.Sprintf("Total Cost (%s)", period.Label())), ac.FormatMoney(cost.Decimal))
+	if period.Name != Yearly {
+		costString += fmt.Sprintf("\n%s:    %v", bold.Sprint("Annualized Cost (per year)"), Accounting(cost.Currency, period.Annual()).FormatMoney(annualCost.Decimal))
+	}
 	if len(unsupportedServices) == 3 {
 		costString = fmt.Sprintf("%s\n- Resource types %s, %s and %s not supported", costString, unsupportedServices[0], unsupportedServices[1], unsupportedServices[2])
 	} else if len(unsupportedServices) == 2 {
@@ -233,6 +275,9 @@ func (s *State) CostString() (string, error) {
 	} else if len(unsupportedServices) > 3 {
 		costString = fmt.Sprintf("%s\n- Resource types %s, %s, %s and %d other Resource types not supported", costString, unsupportedServices[0], unsupportedServices[1], unsupportedServices[2], len(unsupportedServices)-3)
 	}
+	if diagnosticResources > 0 {
+		costString = fmt.Sprintf("%s\n- %d resources have values that could not be evaluated (⚠), their costs may be inaccurate", costString, diagnosticResources)
+	}
 
 	return costString, nil
 }
@@ -0,0 +1,43 @@
+package diagnostic
+
+import "fmt"
+
+// Kind is the kind of issue found while evaluating a resource
+type Kind string
+
+const (
+	// UnresolvedReference is a reference to a resource, data source or module output that could not be resolved
+	UnresolvedReference Kind = "unresolved_reference"
+	// UnknownAfterApply is an attribute whose value is only known once the plan is applied
+	UnknownAfterApply Kind = "unknown_after_apply"
+	// MissingVariable is a reference to a variable that has no value
+	MissingVariable Kind = "missing_variable"
+	// UnsupportedExpression is an expression that can't be evaluated, ex: a reference to a local value
+	UnsupportedExpression Kind = "unsupported_expression"
+	// InvalidValue is a value that is not valid for the attribute, ex: an unknown location
+	InvalidValue Kind = "invalid_value"
+)
+
+// Diagnostic is an issue found on an attribute of a resource, the cost of the resource may be
+// inaccurate as the attribute value is not the one that will be deployed
+type Diagnostic struct {
+	Kind Kind `json:"kind"`
+	// Attribute is the path of the attribute on the resource, ex: root_block_device.0.volume_size
+	Attribute string `json:"attribute"`
+	Message   string `json:"message"`
+}
+
+// String returns the diagnostic as a message for the attribute
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Attribute, d.Message)
+}
+
+// WithPrefix returns the diagnostics with the prefix added to their attributes, used for the attributes of nested blocks
+func WithPrefix(prefix string, diagnostics []Diagnostic) []Diagnostic {
+	prefixed := make([]Diagnostic, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		d.Attribute = fmt.Sprintf("%s.%s", prefix, d.Attribute)
+		prefixed = append(prefixed, d)
+	}
+	return prefixed
+}
@@ -0,0 +1,100 @@
+package kubernetes
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/kaytu-io/pennywise/pkg/cost"
+	"github.com/shopspring/decimal"
+)
+
+// UnallocatedAddress is the address of the resource with the cost of the nodes capacity not requested by the workloads
+const UnallocatedAddress = "unallocated"
+
+// Estimate returns the costs of the workloads as a module per namespace, the cost of a workload is the cost of
+// the nodes share it requests: replicas x max(cpu / node cpu, memory / node memory). nodePrice is the cost of a
+// node. The DaemonSets run a pod per node, if nodes is zero the number of nodes is the minimum number of
+// nodes the workloads fit in. The capacity of the nodes not requested by the workloads is in the root module.
+func Estimate(workloads []Workload, node Node, nodePrice cost.Resource, nodes int) cost.ModularState {
+	// The share of a node requested by the other workloads and by the DaemonSets on every node
+	var share, perNodeShare float64
+	for _, w := range workloads {
+		if w.PerNode() {
+			perNodeShare += nodeShare(w, node)
+		} else {
+			share += float64(w.Replicas) * nodeShare(w, node)
+		}
+	}
+	if nodes <= 0 {
+		nodes = 1
+		if perNodeShare < 1 {
+			nodes = max(nodes, int(math.Ceil(share/(1-perNodeShare)-1e-9)))
+		}
+	}
+
+	state := cost.ModularState{
+		ChildModules: make(map[string]cost.ModularState),
+		Resources:    make(map[string]cost.Resource),
+	}
+	for _, w := range workloads {
+		module, ok := state.ChildModules[w.Namespace]
+		if !ok {
+			module = cost.ModularState{
+				ChildModules: make(map[string]cost.ModularState),
+				Resources:    make(map[string]cost.Resource),
+			}
+		}
+		replicas := w.Replicas
+		if w.PerNode() {
+			replicas = nodes
+		}
+		address := fmt.Sprintf("%s.%s", w.Namespace, w.Address())
+		module.Resources[address] = scaleResource(nodePrice, address, "kubernetes_"+strings.ToLower(w.Kind),
+			float64(replicas)*nodeShare(w, node), workloadDetails(w, replicas))
+		state.ChildModules[w.Namespace] = module
+	}
+
+	if unallocated := float64(nodes)*(1-perNodeShare) - share; unallocated > 1e-9 {
+		state.Resources[UnallocatedAddress] = scaleResource(nodePrice, UnallocatedAddress, "kubernetes_node",
+			unallocated, []string{fmt.Sprintf("%d x %s nodes, capacity not requested by the workloads", nodes, node.InstanceType)})
+	}
+	return state
+}
+
+// nodeShare returns the share of a node requested by a pod of the workload
+func nodeShare(w Workload, node Node) float64 {
+	return max(w.CPU/node.CPU, w.Memory/node.Memory)
+}
+
+// scaleResource returns the resource with the costs of the node components multiplied by the nodes
+func scaleResource(nodePrice cost.Resource, address, typ string, nodes float64, details []string) cost.Resource {
+	factor := decimal.NewFromFloat(nodes).Round(6)
+	res := cost.Resource{
+		Address:     address,
+		Provider:    nodePrice.Provider,
+		Type:        typ,
+		Components:  make(map[string][]cost.Component),
+		IsSupported: true,
+	}
+	for name, components := range nodePrice.Components {
+		for _, c := range components {
+			c.HourlyQuantity = c.HourlyQuantity.Mul(factor)
+			c.MonthlyQuantity = c.MonthlyQuantity.Mul(factor)
+			c.Details = append(append([]string{}, c.Details...), details...)
+			res.Components[name] = append(res.Components[name], c)
+		}
+	}
+	return res
+}
+
+func workloadDetails(w Workload, replicas int) []string {
+	details := []string{fmt.Sprintf("%d replicas, %.3g vCPU and %.3g GiB per replica", replicas, w.CPU, w.Memory)}
+	if w.PerNode() {
+		details[0] = fmt.Sprintf("%d replicas (one per node), %.3g vCPU and %.3g GiB per replica", replicas, w.CPU, w.Memory)
+	}
+	if w.MaxReplicas > 0 {
+		details = append(details, fmt.Sprintf("autoscaled from %d to %d replicas", w.MinReplicas, w.MaxReplicas))
+	}
+	return details
+}
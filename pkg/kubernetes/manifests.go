@@ -0,0 +1,268 @@
+package kubernetes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultNamespace is the namespace of the objects that don't define one
+const DefaultNamespace = "default"
+
+// Workload is a set of pods with the same resource requests
+type Workload struct {
+	Namespace string
+	Kind      string
+	Name      string
+	// Replicas is the number of pods, for the workloads scaled by a HorizontalPodAutoscaler it's
+	// the minimum or maximum number of replicas. It's not used for the DaemonSets which run a pod per node.
+	Replicas int
+	// MinReplicas and MaxReplicas are the replicas range of the HorizontalPodAutoscaler of the workload, if any
+	MinReplicas int
+	MaxReplicas int
+	// CPU is the requested cores of a pod
+	CPU float64
+	// Memory is the requested memory of a pod in GiB
+	Memory float64
+}
+
+// PerNode returns true if the workload runs a pod on every node
+func (w Workload) PerNode() bool {
+	return w.Kind == "DaemonSet"
+}
+
+// Address returns the address of the workload in its namespace, ex: deployment.web
+func (w Workload) Address() string {
+	return fmt.Sprintf("%s.%s", strings.ToLower(w.Kind), w.Name)
+}
+
+type Object struct {
+	Kind     string   `yaml:"kind"`
+	Metadata metadata `yaml:"metadata"`
+	Spec     spec     `yaml:"spec"`
+	Items    []Object `yaml:"items"`
+}
+
+type metadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+// spec contains the fields used of the workloads, pods and HorizontalPodAutoscalers specs
+type spec struct {
+	Replicas *int `yaml:"replicas"`
+	Template *struct {
+		Spec spec `yaml:"spec"`
+	} `yaml:"template"`
+
+	Containers     []container `yaml:"containers"`
+	InitContainers []container `yaml:"initContainers"`
+
+	ScaleTargetRef struct {
+		Kind string `yaml:"kind"`
+		Name string `yaml:"name"`
+	} `yaml:"scaleTargetRef"`
+	MinReplicas *int `yaml:"minReplicas"`
+	MaxReplicas int  `yaml:"maxReplicas"`
+}
+
+type container struct {
+	Resources struct {
+		Requests map[string]string `yaml:"requests"`
+		Limits   map[string]string `yaml:"limits"`
+	} `yaml:"resources"`
+}
+
+// ReadManifests reads the Kubernetes objects of the manifest files, the directories are read recursively.
+// The files can have multiple documents, as the output of helm template.
+func ReadManifests(paths []string) ([]Object, error) {
+	var objects []Object
+	for _, path := range paths {
+		var files []string
+		err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			switch filepath.Ext(p) {
+			case ".yaml", ".yml", ".json":
+				files = append(files, p)
+			default:
+				// The files given explicitly are read whatever their extension is
+				if p == path {
+					files = append(files, p)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifests %s: %w", path, err)
+		}
+		sort.Strings(files)
+		for _, f := range files {
+			content, err := os.ReadFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read manifest %s: %w", f, err)
+			}
+			fileObjects, err := decodeManifest(content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse manifest %s: %w", f, err)
+			}
+			objects = append(objects, fileObjects...)
+		}
+	}
+	return objects, nil
+}
+
+func decodeManifest(content []byte) ([]Object, error) {
+	var objects []Object
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var o Object
+		err := decoder.Decode(&o)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(o.Kind, "List") {
+			objects = append(objects, o.Items...)
+			continue
+		}
+		if o.Kind != "" {
+			objects = append(objects, o)
+		}
+	}
+	return objects, nil
+}
+
+// GetWorkloads returns the workloads of the objects with their replicas, the workloads scaled by a
+// HorizontalPodAutoscaler have its minimum replicas, or its maximum replicas if useMaxReplicas is true.
+// The Jobs and CronJobs are skipped and returned as warnings.
+func GetWorkloads(objects []Object, useMaxReplicas bool) ([]Workload, []string, error) {
+	autoscalers := make(map[string]spec)
+	for _, o := range objects {
+		if o.Kind == "HorizontalPodAutoscaler" {
+			key := fmt.Sprintf("%s/%s/%s", namespace(o), o.Spec.ScaleTargetRef.Kind, o.Spec.ScaleTargetRef.Name)
+			autoscalers[key] = o.Spec
+		}
+	}
+
+	var workloads []Workload
+	var warnings []string
+	for _, o := range objects {
+		var pod spec
+		replicas := 1
+		switch o.Kind {
+		case "Deployment", "StatefulSet", "ReplicaSet", "ReplicationController":
+			if o.Spec.Replicas != nil {
+				replicas = *o.Spec.Replicas
+			}
+			if o.Spec.Template != nil {
+				pod = o.Spec.Template.Spec
+			}
+		case "DaemonSet":
+			if o.Spec.Template != nil {
+				pod = o.Spec.Template.Spec
+			}
+		case "Job", "CronJob":
+			// The pods of the jobs only run part of the month and their runtime is unknown
+			warnings = append(warnings, fmt.Sprintf("%s %s/%s is not estimated, the jobs don't run for the whole month", o.Kind, namespace(o), o.Metadata.Name))
+			continue
+		case "Pod":
+			pod = o.Spec
+		default:
+			continue
+		}
+
+		cpu, memory, err := podRequests(pod)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read the resources of %s %s: %w", o.Kind, o.Metadata.Name, err)
+		}
+		w := Workload{
+			Namespace: namespace(o),
+			Kind:      o.Kind,
+			Name:      o.Metadata.Name,
+			Replicas:  replicas,
+			CPU:       cpu,
+			Memory:    memory,
+		}
+		if hpa, ok := autoscalers[fmt.Sprintf("%s/%s/%s", w.Namespace, o.Kind, o.Metadata.Name)]; ok {
+			w.MinReplicas = 1
+			if hpa.MinReplicas != nil {
+				w.MinReplicas = *hpa.MinReplicas
+			}
+			w.MaxReplicas = hpa.MaxReplicas
+			w.Replicas = w.MinReplicas
+			if useMaxReplicas {
+				w.Replicas = w.MaxReplicas
+			}
+		}
+		workloads = append(workloads, w)
+	}
+	return workloads, warnings, nil
+}
+
+// podRequests returns the cpu and memory requested by a pod, the init containers run before the containers
+// so the pod requests the maximum of the init containers requests and the sum of the containers requests
+func podRequests(pod spec) (float64, float64, error) {
+	var cpu, memory float64
+	for _, c := range pod.Containers {
+		cCPU, cMemory, err := containerRequests(c)
+		if err != nil {
+			return 0, 0, err
+		}
+		cpu += cCPU
+		memory += cMemory
+	}
+	for _, c := range pod.InitContainers {
+		cCPU, cMemory, err := containerRequests(c)
+		if err != nil {
+			return 0, 0, err
+		}
+		cpu = max(cpu, cCPU)
+		memory = max(memory, cMemory)
+	}
+	return cpu, memory, nil
+}
+
+// containerRequests returns the cpu and memory requested by a container, the limits are used
+// when there are no requests as kubernetes does
+func containerRequests(c container) (float64, float64, error) {
+	value := func(name string) (float64, error) {
+		q, ok := c.Resources.Requests[name]
+		if !ok {
+			q, ok = c.Resources.Limits[name]
+		}
+		if !ok {
+			return 0, nil
+		}
+		return ParseQuantity(q)
+	}
+	cpu, err := value("cpu")
+	if err != nil {
+		return 0, 0, err
+	}
+	memory, err := value("memory")
+	if err != nil {
+		return 0, 0, err
+	}
+	return cpu, memory / (1 << 30), nil
+}
+
+func namespace(o Object) string {
+	if o.Metadata.Namespace == "" {
+		return DefaultNamespace
+	}
+	return o.Metadata.Namespace
+}
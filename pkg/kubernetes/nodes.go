@@ -0,0 +1,189 @@
+package kubernetes
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kaytu-io/pennywise/pkg/parser/azurerm/location"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+)
+
+// Node is the instance the workloads are scheduled on
+type Node struct {
+	Provider     schema.ProviderName
+	InstanceType string
+	Region       string
+	// CPU is the number of vCPUs of the node
+	CPU float64
+	// Memory is the memory of the node in GiB
+	Memory float64
+}
+
+type instanceSpec struct {
+	CPU    float64 `json:"cpu"`
+	Memory float64 `json:"memory"`
+}
+
+// instanceSpecsJSON are the vCPUs and memory (GiB) of the common node instance types of each provider, the
+// specs of other instance types have to be given with the node cpu and memory options
+//
+//go:embed instance_specs.json
+var instanceSpecsJSON []byte
+
+var instanceSpecs map[schema.ProviderName]map[string]instanceSpec
+
+func init() {
+	if err := json.Unmarshal(instanceSpecsJSON, &instanceSpecs); err != nil {
+		panic(err)
+	}
+}
+
+// NewNode returns the node of the instance type, the cpu and memory override the specs of the instance type
+// and are required for the instance types that aren't known
+func NewNode(provider schema.ProviderName, instanceType, region string, cpu, memory float64) (*Node, error) {
+	providerSpecs, ok := instanceSpecs[provider]
+	if !ok {
+		return nil, fmt.Errorf("provider %s is not supported, supported providers are %s and %s", provider, schema.AWSProvider, schema.AzureProvider)
+	}
+	if provider == schema.AzureProvider {
+		var err error
+		region, err = location.Normalize(region)
+		if err != nil {
+			return nil, err
+		}
+	}
+	spec, ok := providerSpecs[instanceType]
+	if !ok {
+		// Azure sizes are case insensitive
+		for name, s := range providerSpecs {
+			if strings.EqualFold(name, instanceType) {
+				spec, ok = s, true
+				break
+			}
+		}
+	}
+	if cpu > 0 {
+		spec.CPU = cpu
+	}
+	if memory > 0 {
+		spec.Memory = memory
+	}
+	if spec.CPU <= 0 || spec.Memory <= 0 {
+		return nil, fmt.Errorf("the cpu and memory of instance type %s are unknown, they have to be given with the node cpu and memory", instanceType)
+	}
+	return &Node{
+		Provider:     provider,
+		InstanceType: instanceType,
+		Region:       region,
+		CPU:          spec.CPU,
+		Memory:       spec.Memory,
+	}, nil
+}
+
+// GetNodeGroup returns the node group resource of the project module with the name or address, the node
+// groups are the aws_eks_node_group, azurerm_kubernetes_cluster_node_pool and azurerm_kubernetes_cluster
+// (its default node pool) resources. If name is empty the node group is returned if there is only one.
+func GetNodeGroup(module schema.ModuleDef, name string) (*schema.ResourceDef, error) {
+	var groups []schema.ResourceDef
+	for _, res := range moduleResources(module) {
+		if _, ok := nodeGroupInstanceType(res); !ok {
+			continue
+		}
+		if name == "" || res.Address == name || res.Name == name || fmt.Sprintf("%s.%s", res.Type, res.Name) == name {
+			groups = append(groups, res)
+		}
+	}
+	if len(groups) == 0 {
+		if name == "" {
+			return nil, fmt.Errorf("no node group found in the project")
+		}
+		return nil, fmt.Errorf("node group %s not found in the project", name)
+	}
+	if len(groups) > 1 {
+		var addresses []string
+		for _, g := range groups {
+			addresses = append(addresses, g.Address)
+		}
+		return nil, fmt.Errorf("multiple node groups found, choose one of: %s", strings.Join(addresses, ", "))
+	}
+	return &groups[0], nil
+}
+
+// NewNodeFromGroup returns the node of a node group resource, the region is used if the node
+// group region is unknown. The cpu and memory override the specs of the instance type.
+func NewNodeFromGroup(group schema.ResourceDef, region string, cpu, memory float64) (*Node, error) {
+	instanceType, _ := nodeGroupInstanceType(group)
+	if instanceType == "" {
+		return nil, fmt.Errorf("the instance type of node group %s is unknown", group.Address)
+	}
+	if group.RegionCode != "" {
+		region = group.RegionCode
+	}
+	return NewNode(group.ProviderName, instanceType, region, cpu, memory)
+}
+
+// nodeGroupInstanceType returns the instance type of the node group resource, it returns false if the
+// resource is not a node group
+func nodeGroupInstanceType(res schema.ResourceDef) (string, bool) {
+	switch res.Type {
+	case "aws_eks_node_group":
+		types, _ := res.Values["instance_types"].([]interface{})
+		if len(types) == 0 {
+			// The default instance type of the EKS node groups
+			return "t3.medium", true
+		}
+		instanceType, _ := types[0].(string)
+		return instanceType, true
+	case "azurerm_kubernetes_cluster_node_pool":
+		size, _ := res.Values["vm_size"].(string)
+		return size, true
+	case "azurerm_kubernetes_cluster":
+		pools, _ := res.Values["default_node_pool"].([]interface{})
+		if len(pools) == 0 {
+			return "", true
+		}
+		pool, _ := pools[0].(map[string]interface{})
+		size, _ := pool["vm_size"].(string)
+		return size, true
+	}
+	return "", false
+}
+
+// ResourceDef returns the resource priced as the node
+func (n Node) ResourceDef() schema.ResourceDef {
+	address := fmt.Sprintf("node.%s", n.InstanceType)
+	res := schema.ResourceDef{
+		Address:      address,
+		Name:         n.InstanceType,
+		RegionCode:   n.Region,
+		ProviderName: n.Provider,
+	}
+	switch n.Provider {
+	case schema.AzureProvider:
+		res.Type = "azurerm_linux_virtual_machine"
+		res.Values = map[string]interface{}{
+			"size":     n.InstanceType,
+			"location": n.Region,
+			"os_disk": []interface{}{
+				map[string]interface{}{"storage_account_type": "Premium_LRS", "disk_size_gb": 128},
+			},
+		}
+	default:
+		res.Type = "aws_instance"
+		res.Values = map[string]interface{}{
+			"instance_type": n.InstanceType,
+		}
+	}
+	return res
+}
+
+func moduleResources(module schema.ModuleDef) []schema.ResourceDef {
+	var resources []schema.ResourceDef
+	resources = append(resources, module.Resources...)
+	for _, child := range module.ChildModules {
+		resources = append(resources, moduleResources(child)...)
+	}
+	return resources
+}
@@ -6,12 +6,14 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/kaytu-io/pennywise/pkg/cost"
+	"github.com/kaytu-io/pennywise/pkg/diagnostic"
 	"golang.org/x/crypto/ssh/terminal"
 )
 
 type ComponentsModel struct {
 	label          string
 	table          table.Model
+	diagnostics    []diagnostic.Diagnostic
 	resourcesModel ResourcesModel
 }
 
@@ -35,11 +37,15 @@ func (m ComponentsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m ComponentsModel) View() string {
 	output := "Navigate to resources by pressing ← Quit by pressing Q or [CTRL+C]\n\n"
 	output += bold.Sprint(m.label) + "\n" + baseStyle.Render(m.table.View()) + "\n"
+	for _, d := range m.diagnostics {
+		output += fmt.Sprintf("⚠ %s\n", d.String())
+	}
 	output += "To learn how to use usage open:\nhttps://github.com/kaytu-io/pennywise/blob/main/docs/usage.md"
 	return output
 }
 
-func getComponentsModel(resourceName, resourceCost string, components map[string][]cost.Component, resModel ResourcesModel) (tea.Model, error) {
+func getComponentsModel(resourceName, resourceCost string, resource cost.Resource, resModel ResourcesModel) (tea.Model, error) {
+	components := resource.Components
 	var longestName int
 	for _, comps := range components {
 		for _, c := range comps {
@@ -61,7 +67,7 @@ func getComponentsModel(resourceName, resourceCost string, components map[string
 		{Title: "Hourly Qty", Width: 12},
 		{Title: "Monthly Qty", Width: 12},
 		{Title: "Unit", Width: 14},
-		{Title: "Monthly Cost", Width: 15},
+		{Title: resModel.period.Title() + " Cost", Width: 15},
 	}
 
 	var rows []table.Row
@@ -69,12 +75,12 @@ func getComponentsModel(resourceName, resourceCost string, components map[string
 	for _, comps := range components {
 		for _, c := range comps {
 			var row table.Row
-			row = append(row, c.Name, c.Rate.Decimal.String(), c.HourlyQuantity.String(), c.MonthlyQuantity.String(), c.Unit, c.Cost().Decimal.String())
+			row = append(row, c.Name, c.Rate.Decimal.String(), c.HourlyQuantity.String(), c.MonthlyQuantity.String(), c.Unit, c.PeriodCost(resModel.period).Decimal.String())
 			rows = append(rows, row)
 		}
 	}
 	rows = sortRows(rows)
-	rows = makeNumbersAccounting(rows)
+	rows = makeNumbersAccounting(rows, resModel.period, resModel.currency)
 
 	t := table.New(
 		table.WithColumns(columns),
@@ -96,6 +102,6 @@ func getComponentsModel(resourceName, resourceCost string, components map[string
 		BorderForeground(lipgloss.Color("240")).
 		BorderLeft(true).BorderBottom(false).BorderRight(false).BorderTop(false)
 	t.SetStyles(s)
-	m := ComponentsModel{fmt.Sprintf("%s, Resource Total Cost: %s", resourceName, resourceCost), t, resModel}
+	m := ComponentsModel{fmt.Sprintf("%s, Resource Total Cost: %s", resourceName, resourceCost), t, resource.Diagnostics, resModel}
 	return m, nil
 }
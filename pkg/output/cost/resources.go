@@ -6,7 +6,6 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/kaytu-io/pennywise/pkg/cost"
-	"github.com/leekchan/accounting"
 	"golang.org/x/crypto/ssh/terminal"
 )
 
@@ -22,6 +21,8 @@ type ResourcesModel struct {
 	freeResources        []string
 	unsupportedResources map[string][]string
 	longestName          int
+	period               cost.Period
+	currency             string
 }
 
 func (m ResourcesModel) Init() tea.Cmd { return nil }
@@ -53,17 +54,13 @@ func (m ResourcesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return unsupportedModel, cmd
 			}
 			if resource, ok := m.state.Resources[name]; ok {
-				compsModel, err := getComponentsModel(name, m.table.SelectedRow()[2], resource.Components, m)
+				compsModel, err := getComponentsModel(name, m.table.SelectedRow()[2], resource, m)
 				if err != nil {
 					panic(err)
 				}
 				return compsModel, cmd
 			} else {
 				module := m.state.ChildModules[name]
-				moduleCost, err := module.Cost()
-				if err != nil {
-					panic(err)
-				}
 				var longestName int
 				for n, _ := range module.Resources {
 					if len(n) > longestName {
@@ -75,9 +72,11 @@ func (m ResourcesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						longestName = len(n)
 					}
 				}
-				ac := accounting.Accounting{Symbol: "$", Precision: 2}
-				label := fmt.Sprintf("Module total Cost: %s", ac.FormatMoney(moduleCost.Decimal))
-				resModel, err := getResourcesModel(label, &module, longestName, &m)
+				label, err := totalLabel("Module total Cost", &module, m.period, m.currency)
+				if err != nil {
+					panic(err)
+				}
+				resModel, err := getResourcesModel(label, &module, longestName, m.period, m.currency, &m)
 				if err != nil {
 					panic(err)
 				}
@@ -92,22 +91,25 @@ func (m ResourcesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m ResourcesModel) View() string {
 	output := "Navigate to details by pressing → or [ENTER] Quit by pressing Q or [CTRL+C]\n\n"
 	output += bold.Sprint(m.label) + "\n" + baseStyle.Render(m.table.View()) + "\n"
+	if count := m.state.DiagnosticResourcesCount(); count > 0 {
+		output += fmt.Sprintf("⚠ %d resources have values that could not be evaluated, their costs may be inaccurate. Their details show the issues.\n", count)
+	}
 	output += "To learn how to use usage open:\nhttps://github.com/kaytu-io/pennywise/blob/main/docs/usage.md"
 	return output
 }
 
-func getResourcesModel(label string, state *cost.ModularState, longestName int, parentModel *ResourcesModel) (tea.Model, error) {
+func getResourcesModel(label string, state *cost.ModularState, longestName int, period cost.Period, currency string, parentModel *ResourcesModel) (tea.Model, error) {
 	w, _, err := terminal.GetSize(0)
 	if err != nil {
 		return nil, err
 	}
 	if (longestName + 33) > w {
-		return getSmallTerminalModelModel(label, state, w-36, parentModel)
+		return getSmallTerminalModelModel(label, state, w-36, period, currency, parentModel)
 	}
 	columns := []table.Column{
 		{Title: "Name", Width: longestName},
 		{Title: "Resources", Width: 10},
-		{Title: "Monthly Cost", Width: 12},
+		{Title: period.Title() + " Cost", Width: 12},
 	}
 
 	var rows []table.Row
@@ -115,7 +117,7 @@ func getResourcesModel(label string, state *cost.ModularState, longestName int,
 	unsupportedServices := make(map[string][]string)
 
 	for name, module := range state.ChildModules {
-		cost, err := module.Cost()
+		cost, err := module.PeriodCost(period)
 		if err != nil {
 			return nil, err
 		}
@@ -130,7 +132,7 @@ func getResourcesModel(label string, state *cost.ModularState, longestName int,
 			unsupportedServices[resource.Type] = append(unsupportedServices[resource.Type], name)
 			continue
 		}
-		cost, err := resource.Cost()
+		cost, err := resource.PeriodCost(period)
 		if err != nil {
 			return nil, err
 		}
@@ -147,7 +149,7 @@ func getResourcesModel(label string, state *cost.ModularState, longestName int,
 		rows = append(rows, []string{"Unsupported", fmt.Sprintf("%d", len(unsupportedServices)), "0"})
 	}
 	rows = sortRows(rows)
-	rows = makeNumbersAccounting(rows)
+	rows = makeNumbersAccounting(rows, period, currency)
 	columns = append(columns, table.Column{Title: "", Width: 1})
 	for i, _ := range rows {
 		rows[i] = append(rows[i], "→")
@@ -173,6 +175,6 @@ func getResourcesModel(label string, state *cost.ModularState, longestName int,
 		BorderLeft(true).BorderBottom(false).BorderRight(false).BorderTop(false)
 	t.SetStyles(s)
 
-	m := ResourcesModel{label, t, state, parentModel, freeResources, unsupportedServices, longestName}
+	m := ResourcesModel{label, t, state, parentModel, freeResources, unsupportedServices, longestName, period, currency}
 	return m, nil
 }
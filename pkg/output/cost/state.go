@@ -4,15 +4,11 @@ import (
 	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/kaytu-io/pennywise/pkg/cost"
-	"github.com/leekchan/accounting"
 	"os"
 )
 
-func ShowStateCosts(s *cost.ModularState) error {
-	totalCost, err := s.Cost()
-	if err != nil {
-		return err
-	}
+// ShowStateCosts shows the costs of the state for the period in the currency, with its annualized total cost
+func ShowStateCosts(s *cost.ModularState, period cost.Period, currency string) error {
 	var longestName int
 	for name, _ := range s.Resources {
 		if len(name) > longestName {
@@ -24,9 +20,11 @@ func ShowStateCosts(s *cost.ModularState) error {
 			longestName = len(name)
 		}
 	}
-	ac := accounting.Accounting{Symbol: "$", Precision: 2}
-	label := fmt.Sprintf("Total Cost: %s", ac.FormatMoney(totalCost.Decimal))
-	model, err := getResourcesModel(label, s, longestName, nil)
+	label, err := totalLabel("Total Cost", s, period, currency)
+	if err != nil {
+		return err
+	}
+	model, err := getResourcesModel(label, s, longestName, period, currency, nil)
 	if err != nil {
 		return err
 	}
@@ -1,9 +1,10 @@
 package cost
 
 import (
+	"fmt"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/fatih/color"
-	"github.com/leekchan/accounting"
+	"github.com/kaytu-io/pennywise/pkg/cost"
 	"sort"
 	"strconv"
 )
@@ -20,11 +21,29 @@ func sortRows(rows []table.Row) []table.Row {
 	return rows
 }
 
-func makeNumbersAccounting(rows []table.Row) []table.Row {
-	ac := accounting.Accounting{Symbol: "$", Precision: 2}
+func makeNumbersAccounting(rows []table.Row, period cost.Period, currency string) []table.Row {
+	ac := cost.Accounting(currency, period)
 	for _, row := range rows {
 		costFloat, _ := strconv.ParseFloat(row[len(row)-1], 64)
 		row[len(row)-1] = ac.FormatMoney(costFloat)
 	}
 	return rows
 }
+
+// totalLabel returns the label of the total cost of the state for the period in the currency, with the annualized
+// cost
+func totalLabel(name string, state *cost.ModularState, period cost.Period, currency string) (string, error) {
+	total, err := state.PeriodCost(period)
+	if err != nil {
+		return "", err
+	}
+	label := fmt.Sprintf("%s (%s): %s", name, period.Label(), cost.Accounting(currency, period).FormatMoney(total.Decimal))
+	if period.Name != cost.Yearly {
+		annualTotal, err := state.PeriodCost(period.Annual())
+		if err != nil {
+			return "", err
+		}
+		label += fmt.Sprintf(", Annualized: %s", cost.Accounting(currency, period.Annual()).FormatMoney(annualTotal.Decimal))
+	}
+	return label, nil
+}
@@ -5,14 +5,16 @@ import (
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/kaytu-io/pennywise/pkg/cost"
+	"github.com/kaytu-io/pennywise/pkg/diagnostic"
 	"github.com/kaytu-io/pennywise/pkg/schema"
-	"github.com/leekchan/accounting"
 	"golang.org/x/crypto/ssh/terminal"
 )
 
 type ComponentsModel struct {
 	label          string
 	table          table.Model
+	diagnostics    []diagnostic.Diagnostic
 	resourcesModel ResourcesModel
 }
 
@@ -36,11 +38,15 @@ func (m ComponentsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m ComponentsModel) View() string {
 	output := "Navigate to resources by pressing ← Quit by pressing Q or [CTRL+C]\n\n"
 	output += bold.Sprint(m.label) + "\n" + baseStyle.Render(m.table.View()) + "\n"
+	for _, d := range m.diagnostics {
+		output += fmt.Sprintf("⚠ %s\n", d.String())
+	}
 	output += "To learn how to use usage open:\nhttps://github.com/kaytu-io/pennywise/blob/main/docs/usage.md"
 	return output
 }
 
-func getComponentsModel(resourceName, resourceCost string, components map[string][]schema.ComponentDiff, resModel ResourcesModel) (tea.Model, error) {
+func getComponentsModel(resourceName, resourceCost string, resource schema.ResourceDiff, resModel ResourcesModel) (tea.Model, error) {
+	components := resource.ComponentDiffs
 	var longestName int
 	for _, comps := range components {
 		for _, c := range comps {
@@ -62,11 +68,11 @@ func getComponentsModel(resourceName, resourceCost string, components map[string
 		{Title: "Hourly Qty", Width: 20},
 		{Title: "Monthly Qty", Width: 20},
 		{Title: "Unit", Width: 10},
-		{Title: "Monthly Cost", Width: 25},
+		{Title: resModel.period.Title() + " Cost", Width: 25},
 	}
 
 	var rows []table.Row
-	ac := accounting.Accounting{Symbol: "$", Precision: 2}
+	ac := cost.Accounting(resModel.currency, resModel.period)
 
 	for _, comps := range components {
 		for _, c := range comps {
@@ -76,13 +82,14 @@ func getComponentsModel(resourceName, resourceCost string, components map[string
 			var hourlyCost string
 			var monthlyCost string
 			var costDiff string
+			priorCost, newCost := c.PeriodCosts(resModel.period)
 			switch c.Action {
 			case schema.ActionCreate:
 				componentName = green.Sprint("+ ") + c.Component.Name
 				rateString = c.Component.Rate.Decimal.String()
 				hourlyCost = c.Component.HourlyQuantity.String()
 				monthlyCost = c.Component.MonthlyQuantity.String()
-				costDiff = "+" + ac.FormatMoney(c.CostDiff)
+				costDiff = "+" + ac.FormatMoney(newCost)
 			case schema.ActionModify:
 				componentName = yellow.Sprint("~ ") + c.Component.Name
 				if !c.Component.Rate.Decimal.IsZero() {
@@ -99,8 +106,8 @@ func getComponentsModel(resourceName, resourceCost string, components map[string
 					monthlyCost = monthlyCost +
 						fmt.Sprintf(" (%s -> %s)", c.CompareTo.MonthlyQuantity.String(), c.Current.MonthlyQuantity.String())
 				}
-				costDiff = ac.FormatMoney(c.CostDiff) +
-					fmt.Sprintf(" (%s -> %s)", ac.FormatMoney(c.CompareTo.Cost().Decimal), ac.FormatMoney(c.Current.Cost().Decimal))
+				costDiff = ac.FormatMoney(newCost.Sub(priorCost)) +
+					fmt.Sprintf(" (%s -> %s)", ac.FormatMoney(priorCost), ac.FormatMoney(newCost))
 				if c.CostDiff.InexactFloat64() > 0 {
 					costDiff = "+" + costDiff
 				}
@@ -109,7 +116,7 @@ func getComponentsModel(resourceName, resourceCost string, components map[string
 				rateString = c.Component.Rate.Decimal.String()
 				hourlyCost = c.Component.HourlyQuantity.String()
 				monthlyCost = c.Component.MonthlyQuantity.String()
-				costDiff = "-" + ac.FormatMoney(c.CostDiff)
+				costDiff = "-" + ac.FormatMoney(priorCost)
 			}
 			row = append(row, componentName, rateString, hourlyCost,
 				monthlyCost, c.Component.Unit, costDiff)
@@ -137,6 +144,6 @@ func getComponentsModel(resourceName, resourceCost string, components map[string
 		BorderForeground(lipgloss.Color("240")).
 		BorderLeft(true).BorderBottom(false).BorderRight(false).BorderTop(false)
 	t.SetStyles(s)
-	m := ComponentsModel{fmt.Sprintf("%s, Resource Total Cost: %s", resourceName, resourceCost), t, resModel}
+	m := ComponentsModel{fmt.Sprintf("%s, Resource Total Cost: %s", resourceName, resourceCost), t, resource.Diagnostics, resModel}
 	return m, nil
 }
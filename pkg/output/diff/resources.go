@@ -5,8 +5,8 @@ import (
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/kaytu-io/pennywise/pkg/cost"
 	"github.com/kaytu-io/pennywise/pkg/schema"
-	"github.com/leekchan/accounting"
 	"golang.org/x/crypto/ssh/terminal"
 )
 
@@ -22,6 +22,8 @@ type ResourcesModel struct {
 	freeResources        []string
 	unsupportedResources map[string][]string
 	longestName          int
+	period               cost.Period
+	currency             string
 }
 
 func (m ResourcesModel) Init() tea.Cmd { return nil }
@@ -40,16 +42,14 @@ func (m ResourcesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "right", "enter":
 			name := m.table.SelectedRow()[0][11:]
 			if resource, ok := m.state.Resources[name]; ok {
-				compsModel, err := getComponentsModel(name, m.table.SelectedRow()[1], resource.ComponentDiffs, m)
+				compsModel, err := getComponentsModel(name, m.table.SelectedRow()[1], resource, m)
 				if err != nil {
 					panic(err)
 				}
 				return compsModel, cmd
 			} else {
 				module := m.state.ChildModules[name]
-				ac := accounting.Accounting{Symbol: "$", Precision: 2}
-				label := fmt.Sprintf("Total Diff: %s (%s -> %s)", ac.FormatMoney(module.NewCost.Sub(module.PriorCost)),
-					ac.FormatMoney(module.PriorCost), ac.FormatMoney(module.NewCost))
+				label := diffLabel(&module, m.period, m.currency)
 				var longestName int
 				for n, _ := range module.Resources {
 					if len(n) > longestName {
@@ -61,7 +61,7 @@ func (m ResourcesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						longestName = len(n)
 					}
 				}
-				resModel, err := getResourcesModel(label, &module, longestName, &m)
+				resModel, err := getResourcesModel(label, &module, longestName, m.period, m.currency, &m)
 				if err != nil {
 					panic(err)
 				}
@@ -76,45 +76,49 @@ func (m ResourcesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m ResourcesModel) View() string {
 	output := "Navigate to details by pressing → or [ENTER] Quit by pressing Q or [CTRL+C]\n\n"
 	output += bold.Sprint(m.label) + "\n" + baseStyle.Render(m.table.View()) + "\n"
+	if count := m.state.DiagnosticResourcesCount(); count > 0 {
+		output += fmt.Sprintf("⚠ %d resources have values that could not be evaluated, their costs may be inaccurate. Their details show the issues.\n", count)
+	}
 	output += "To learn how to use usage open:\nhttps://github.com/kaytu-io/pennywise/blob/main/docs/usage.md"
 	return output
 }
 
-func getResourcesModel(label string, stateDiff *schema.ModularStateDiff, longestName int, parentModel *ResourcesModel) (tea.Model, error) {
+func getResourcesModel(label string, stateDiff *schema.ModularStateDiff, longestName int, period cost.Period, currency string, parentModel *ResourcesModel) (tea.Model, error) {
 	w, _, err := terminal.GetSize(0)
 	if err != nil {
 		return nil, err
 	}
 	if (longestName + 26) > w {
-		return getSmallTerminalModelModel(label, stateDiff, w-29, parentModel)
+		return getSmallTerminalModelModel(label, stateDiff, w-29, period, currency, parentModel)
 	}
 	columns := []table.Column{
 		{Title: "Name", Width: longestName + 11},
 		{Title: "Resources", Width: 10},
-		{Title: "Monthly Cost", Width: 30},
+		{Title: period.Title() + " Cost", Width: 30},
 	}
 
 	var rows []table.Row
 	var freeResources []string
 	unsupportedServices := make(map[string][]string)
-	ac := accounting.Accounting{Symbol: "$", Precision: 2}
+	ac := cost.Accounting(currency, period)
 
 	for name, module := range stateDiff.ChildModules {
 		var costDiff string
+		priorCost, newCost := module.PeriodCosts(period)
 		switch module.Action {
 		case schema.ActionCreate:
 			name = green.Sprint("+ ") + name
-			costDiff = ac.FormatMoney(module.NewCost)
+			costDiff = ac.FormatMoney(newCost)
 		case schema.ActionModify:
 			name = yellow.Sprint("~ ") + name
-			costDiff = ac.FormatMoney(module.NewCost.Sub(module.PriorCost)) +
-				fmt.Sprintf(" (%s -> %s)", ac.FormatMoney(module.PriorCost), ac.FormatMoney(module.NewCost))
-			if module.NewCost.Sub(module.PriorCost).InexactFloat64() > 0 {
+			costDiff = ac.FormatMoney(newCost.Sub(priorCost)) +
+				fmt.Sprintf(" (%s -> %s)", ac.FormatMoney(priorCost), ac.FormatMoney(newCost))
+			if newCost.Sub(priorCost).InexactFloat64() > 0 {
 				costDiff = "+" + costDiff
 			}
 		case schema.ActionRemove:
 			name = red.Sprint("- ") + name
-			costDiff = ac.FormatMoney(module.PriorCost)
+			costDiff = ac.FormatMoney(priorCost)
 		}
 		rows = append(rows, []string{name, fmt.Sprintf("%d", module.TotalResourcesCount()), costDiff})
 	}
@@ -132,20 +136,21 @@ func getResourcesModel(label string, stateDiff *schema.ModularStateDiff, longest
 			continue
 		}
 		var costDiff string
+		priorCost, newCost := resource.PeriodCosts(period)
 		switch resource.Action {
 		case schema.ActionCreate:
 			name = green.Sprint("+ ") + name
-			costDiff = ac.FormatMoney(resource.NewCost)
+			costDiff = ac.FormatMoney(newCost)
 		case schema.ActionModify:
 			name = yellow.Sprint("~ ") + name
-			costDiff = ac.FormatMoney(resource.NewCost.Sub(resource.PriorCost)) +
-				fmt.Sprintf(" (%s -> %s)", ac.FormatMoney(resource.PriorCost), ac.FormatMoney(resource.NewCost))
-			if resource.NewCost.Sub(resource.PriorCost).InexactFloat64() > 0 {
+			costDiff = ac.FormatMoney(newCost.Sub(priorCost)) +
+				fmt.Sprintf(" (%s -> %s)", ac.FormatMoney(priorCost), ac.FormatMoney(newCost))
+			if newCost.Sub(priorCost).InexactFloat64() > 0 {
 				costDiff = "+" + costDiff
 			}
 		case schema.ActionRemove:
 			name = red.Sprint("- ") + name
-			costDiff = ac.FormatMoney(resource.PriorCost)
+			costDiff = ac.FormatMoney(priorCost)
 		}
 		rows = append(rows, []string{name, "", costDiff})
 	}
@@ -174,6 +179,6 @@ func getResourcesModel(label string, stateDiff *schema.ModularStateDiff, longest
 		BorderLeft(true).BorderBottom(false).BorderRight(false).BorderTop(false)
 	t.SetStyles(s)
 
-	m := ResourcesModel{label, t, stateDiff, parentModel, freeResources, unsupportedServices, longestName}
+	m := ResourcesModel{label, t, stateDiff, parentModel, freeResources, unsupportedServices, longestName, period, currency}
 	return m, nil
 }
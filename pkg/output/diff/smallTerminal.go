@@ -2,6 +2,7 @@ package diff
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaytu-io/pennywise/pkg/cost"
 	"github.com/kaytu-io/pennywise/pkg/schema"
 )
 
@@ -10,6 +11,8 @@ type SmallTerminalModel struct {
 	parentModel *ResourcesModel
 	label       string
 	wSize       int
+	period      cost.Period
+	currency    string
 }
 
 func (m SmallTerminalModel) Init() tea.Cmd { return nil }
@@ -22,7 +25,7 @@ func (m SmallTerminalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "esc", "q", "ctrl+c":
 			return m, tea.Quit
 		case "enter":
-			model, err := getResourcesModel(m.label, m.stateDiff, m.wSize, m.parentModel)
+			model, err := getResourcesModel(m.label, m.stateDiff, m.wSize, m.period, m.currency, m.parentModel)
 			if err != nil {
 				panic(err)
 			}
@@ -37,8 +40,8 @@ func (m SmallTerminalModel) View() string {
 		"Exit by pressing [ESC], q or [CTRL+C]"
 }
 
-func getSmallTerminalModelModel(label string, stateDiff *schema.ModularStateDiff, wSize int, parentModel *ResourcesModel) (tea.Model, error) {
+func getSmallTerminalModelModel(label string, stateDiff *schema.ModularStateDiff, wSize int, period cost.Period, currency string, parentModel *ResourcesModel) (tea.Model, error) {
 
-	m := SmallTerminalModel{stateDiff, parentModel, label, wSize}
+	m := SmallTerminalModel{stateDiff, parentModel, label, wSize, period, currency}
 	return m, nil
 }
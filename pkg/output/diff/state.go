@@ -4,8 +4,8 @@ import (
 	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/fatih/color"
+	"github.com/kaytu-io/pennywise/pkg/cost"
 	"github.com/kaytu-io/pennywise/pkg/schema"
-	"github.com/leekchan/accounting"
 	"os"
 )
 
@@ -13,7 +13,8 @@ var yellow = color.New(color.FgYellow)
 var red = color.New(color.FgHiRed)
 var green = color.New(color.FgHiGreen)
 
-func ShowStateCosts(s *schema.ModularStateDiff) error {
+// ShowStateCosts shows the costs diff of the state for the period in the currency, with its annualized total costs
+func ShowStateCosts(s *schema.ModularStateDiff, period cost.Period, currency string) error {
 	var longestName int
 	for name, _ := range s.Resources {
 		if len(name) > longestName {
@@ -26,10 +27,8 @@ func ShowStateCosts(s *schema.ModularStateDiff) error {
 		}
 	}
 
-	ac := accounting.Accounting{Symbol: "$", Precision: 2}
-	label := fmt.Sprintf("Total Diff: %s (%s -> %s)", ac.FormatMoney(s.NewCost.Sub(s.PriorCost)),
-		ac.FormatMoney(s.PriorCost), ac.FormatMoney(s.NewCost))
-	model, err := getResourcesModel(label, s, longestName, nil)
+	label := diffLabel(s, period, currency)
+	model, err := getResourcesModel(label, s, longestName, period, currency, nil)
 	if err != nil {
 		return err
 	}
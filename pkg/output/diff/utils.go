@@ -1,9 +1,11 @@
 package diff
 
 import (
+	"fmt"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/fatih/color"
-	"github.com/leekchan/accounting"
+	"github.com/kaytu-io/pennywise/pkg/cost"
+	"github.com/kaytu-io/pennywise/pkg/schema"
 	"sort"
 	"strconv"
 )
@@ -20,11 +22,25 @@ func sortRows(rows []table.Row) []table.Row {
 	return rows
 }
 
-func makeNumbersAccounting(rows []table.Row) []table.Row {
-	ac := accounting.Accounting{Symbol: "$", Precision: 2}
+func makeNumbersAccounting(rows []table.Row, period cost.Period, currency string) []table.Row {
+	ac := cost.Accounting(currency, period)
 	for _, row := range rows {
 		costFloat, _ := strconv.ParseFloat(row[len(row)-1], 64)
 		row[len(row)-1] = ac.FormatMoney(costFloat)
 	}
 	return rows
 }
+
+// diffLabel returns the label of the total costs diff of the state for the period in the currency, with the
+// annualized costs diff
+func diffLabel(stateDiff *schema.ModularStateDiff, period cost.Period, currency string) string {
+	ac := cost.Accounting(currency, period)
+	priorCost, newCost := stateDiff.PeriodCosts(period)
+	label := fmt.Sprintf("Total Diff (%s): %s (%s -> %s)", period.Label(), ac.FormatMoney(newCost.Sub(priorCost)),
+		ac.FormatMoney(priorCost), ac.FormatMoney(newCost))
+	if period.Name != cost.Yearly {
+		annualPriorCost, annualNewCost := stateDiff.PeriodCosts(period.Annual())
+		label += fmt.Sprintf(", Annualized: %s", cost.Accounting(currency, period.Annual()).FormatMoney(annualNewCost.Sub(annualPriorCost)))
+	}
+	return label
+}
@@ -0,0 +1,191 @@
+package arm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kaytu-io/pennywise/pkg/parser/properties"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	"github.com/kaytu-io/pennywise/pkg/usage"
+)
+
+// Deployment is the result of the evaluation of a template
+type Deployment struct {
+	// Module contains the resources of the template, each nested deployment is a child module
+	Module schema.ModuleDef
+	// Unsupported are the resource types that can not be estimated
+	Unsupported []string
+	// Warnings are the parts of the template that could not be evaluated
+	Warnings []string
+}
+
+// Evaluate evaluates the template with the parameters and returns the terraform equivalent of its resources
+func Evaluate(template *Template, parameters map[string]interface{}, opts Options, u usage.Usage) (*Deployment, error) {
+	ctx, err := newContext(template, parameters, opts, opts.DeploymentName)
+	if err != nil {
+		return nil, err
+	}
+	d := &deploymentEvaluator{usage: u, opts: opts}
+	module, err := d.evaluateTemplate(ctx, template, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Deployment{
+		Module:      *module,
+		Unsupported: d.unsupported,
+		Warnings:    d.warnings,
+	}, nil
+}
+
+type deploymentEvaluator struct {
+	usage       usage.Usage
+	opts        Options
+	unsupported []string
+	warnings    []string
+}
+
+func (d *deploymentEvaluator) evaluateTemplate(ctx *context, template *Template, address string) (*schema.ModuleDef, error) {
+	resources, err := template.resources()
+	if err != nil {
+		return nil, err
+	}
+	module := &schema.ModuleDef{Address: address}
+	for _, r := range resources {
+		if err := d.evaluateResource(ctx, r, nil, module); err != nil {
+			return nil, err
+		}
+	}
+	return module, nil
+}
+
+// evaluateResource evaluates the resource once for each iteration of its copy loop
+func (d *deploymentEvaluator) evaluateResource(ctx *context, r Resource, parent properties.Properties, module *schema.ModuleDef) error {
+	if r.Existing {
+		return nil
+	}
+	if r.Copy == nil {
+		return d.evaluateInstance(ctx, r, parent, module)
+	}
+	count, err := ctx.evaluateCount(r.Copy.Count)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate the count of copy %s: %w", r.Copy.Name, err)
+	}
+	for i := 0; i < count; i++ {
+		err := ctx.withCopyIndex(r.Copy.Name, i, func() error {
+			return d.evaluateInstance(ctx, r, parent, module)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *deploymentEvaluator) evaluateInstance(ctx *context, r Resource, parent properties.Properties, module *schema.ModuleDef) error {
+	if r.Condition != nil {
+		condition, err := ctx.evaluate(r.Condition)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate the condition of resource %s: %w", r.Name, err)
+		}
+		if !toBool(condition) {
+			return nil
+		}
+	}
+
+	res := properties.Properties{}
+	for _, key := range []string{"type", "name"} {
+		v, err := ctx.evaluate(properties.Get(r.Raw, key))
+		if err != nil {
+			return fmt.Errorf("failed to evaluate the %s of resource %s: %w", key, r.Name, err)
+		}
+		res[key] = toString(v)
+	}
+	rType, name := res["type"].(string), res["name"].(string)
+	// Child resources defined inside their parent have a type and a name relative to the parent
+	if parent != nil && !strings.Contains(rType, ".") {
+		rType = parent["type"].(string) + "/" + rType
+		name = parent["name"].(string) + "/" + name
+	}
+	res["type"], res["name"] = rType, name
+
+	if strings.EqualFold(rType, "Microsoft.Resources/deployments") {
+		return d.evaluateDeployment(ctx, r, name, module)
+	}
+
+	for key, raw := range r.Raw {
+		switch strings.ToLower(key) {
+		case "type", "name", "resources", "copy", "condition", "dependson", "comments", "existing":
+			continue
+		}
+		v, err := ctx.evaluate(raw)
+		if err != nil {
+			d.warnings = append(d.warnings, fmt.Sprintf("%s %s: failed to evaluate %s: %s", rType, name, key, err))
+			continue
+		}
+		res[key] = v
+	}
+
+	if location := res.StringOr("", "location"); location == "" || strings.EqualFold(location, "global") {
+		res["location"] = ctx.opts.Location
+	}
+	resources, ok := ResourceDefs(res, module.Address, d.usage)
+	if !ok {
+		d.unsupported = append(d.unsupported, rType)
+	}
+	module.Resources = append(module.Resources, resources...)
+
+	for _, child := range r.Resources {
+		if err := d.evaluateResource(ctx, child, res, module); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluateDeployment evaluates a nested deployment as a child module. With the default outer scope
+// the nested template uses the parameters and variables of its parent, with the inner scope it's
+// evaluated with its own parameters.
+func (d *deploymentEvaluator) evaluateDeployment(ctx *context, r Resource, name string, module *schema.ModuleDef) error {
+	props := properties.Properties(r.Raw).Object("properties")
+	raw := props.Value("template")
+	if raw == nil {
+		d.warnings = append(d.warnings, fmt.Sprintf("deployment %s uses a linked template, its resources are not estimated", name))
+		return nil
+	}
+	content, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	var template Template
+	if err := json.Unmarshal(content, &template); err != nil {
+		return fmt.Errorf("failed to parse the template of deployment %s: %w", name, err)
+	}
+
+	nestedCtx := ctx
+	if strings.EqualFold(props.StringOr("outer", "expressionEvaluationOptions", "scope"), "inner") {
+		parameters := make(map[string]interface{})
+		for key, p := range props.Object("parameters") {
+			v, err := ctx.evaluate(properties.Get(p, "value"))
+			if err != nil {
+				return fmt.Errorf("failed to evaluate parameter %s of deployment %s: %w", key, name, err)
+			}
+			parameters[key] = v
+		}
+		nestedCtx, err = newContext(&template, parameters, ctx.opts, name)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate deployment %s: %w", name, err)
+		}
+	}
+
+	address := name
+	if module.Address != "" {
+		address = module.Address + "." + name
+	}
+	child, err := d.evaluateTemplate(nestedCtx, &template, address)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate deployment %s: %w", name, err)
+	}
+	module.ChildModules = append(module.ChildModules, *child)
+	return nil
+}
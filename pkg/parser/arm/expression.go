@@ -0,0 +1,323 @@
+package arm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kaytu-io/pennywise/pkg/parser/properties"
+)
+
+// expression is a parsed ARM template expression, ex: [concat(parameters('prefix'), '-vm')]
+type expression interface {
+	eval(ctx *context) (interface{}, error)
+}
+
+type literal struct {
+	value interface{}
+}
+
+type functionCall struct {
+	name string
+	args []expression
+}
+
+type propertyAccess struct {
+	target   expression
+	property string
+}
+
+type indexAccess struct {
+	target expression
+	index  expression
+}
+
+func (l literal) eval(ctx *context) (interface{}, error) {
+	return l.value, nil
+}
+
+func (f functionCall) eval(ctx *context) (interface{}, error) {
+	fn, ok := functions[strings.ToLower(f.name)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported function %s", f.name)
+	}
+	// if is evaluated lazily so the branch not taken can't fail
+	if strings.EqualFold(f.name, "if") {
+		if len(f.args) != 3 {
+			return nil, fmt.Errorf("if expects 3 arguments")
+		}
+		cond, err := f.args[0].eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if toBool(cond) {
+			return f.args[1].eval(ctx)
+		}
+		return f.args[2].eval(ctx)
+	}
+	args := make([]interface{}, 0, len(f.args))
+	for _, a := range f.args {
+		v, err := a.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	return fn(ctx, args)
+}
+
+func (p propertyAccess) eval(ctx *context) (interface{}, error) {
+	target, err := p.target.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return properties.Get(target, p.property), nil
+}
+
+func (i indexAccess) eval(ctx *context) (interface{}, error) {
+	target, err := i.target.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index, err := i.index.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch t := target.(type) {
+	case []interface{}:
+		n, ok := toNumber(index)
+		if !ok || int(n) < 0 || int(n) >= len(t) {
+			return nil, fmt.Errorf("index %v out of range", index)
+		}
+		return t[int(n)], nil
+	case map[string]interface{}:
+		return properties.Get(t, toString(index)), nil
+	}
+	// Unknown values (ex: reference to a resource) stay unknown
+	return nil, nil
+}
+
+// isExpression returns true if the string is an expression, strings starting
+// with [[ are escaped literals
+func isExpression(s string) bool {
+	return strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") && !strings.HasPrefix(s, "[[")
+}
+
+// parseExpression parses an expression string including the surrounding brackets
+func parseExpression(s string) (expression, error) {
+	p := &expressionParser{input: []rune(strings.TrimSpace(s[1 : len(s)-1]))}
+	expr, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q in expression %s", string(p.input[p.pos:]), s)
+	}
+	return expr, nil
+}
+
+type expressionParser struct {
+	input []rune
+	pos   int
+}
+
+func (p *expressionParser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *expressionParser) peek() rune {
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *expressionParser) expect(r rune) error {
+	p.skipSpaces()
+	if p.peek() != r {
+		return fmt.Errorf("expected %q at position %d of expression %s", r, p.pos, string(p.input))
+	}
+	p.pos++
+	return nil
+}
+
+func (p *expressionParser) parse() (expression, error) {
+	p.skipSpaces()
+	var expr expression
+	var err error
+	switch c := p.peek(); {
+	case c == '\'':
+		expr, err = p.parseString()
+	case c == '-' || unicode.IsDigit(c):
+		expr, err = p.parseNumber()
+	case unicode.IsLetter(c) || c == '_':
+		expr, err = p.parseFunction()
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d of expression %s", c, p.pos, string(p.input))
+	}
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		switch p.peek() {
+		case '.':
+			p.pos++
+			name := p.parseIdentifier()
+			if name == "" {
+				return nil, fmt.Errorf("expected property name at position %d of expression %s", p.pos, string(p.input))
+			}
+			expr = propertyAccess{target: expr, property: name}
+		case '[':
+			p.pos++
+			index, err := p.parse()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(']'); err != nil {
+				return nil, err
+			}
+			expr = indexAccess{target: expr, index: index}
+		default:
+			return expr, nil
+		}
+	}
+}
+
+func (p *expressionParser) parseString() (expression, error) {
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		p.pos++
+		if c == '\'' {
+			// '' is an escaped quote
+			if p.peek() == '\'' {
+				p.pos++
+				sb.WriteRune('\'')
+				continue
+			}
+			return literal{value: sb.String()}, nil
+		}
+		sb.WriteRune(c)
+	}
+	return nil, fmt.Errorf("unterminated string in expression %s", string(p.input))
+}
+
+func (p *expressionParser) parseNumber() (expression, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.input) && (unicode.IsDigit(p.input[p.pos]) || p.input[p.pos] == '.') {
+		p.pos++
+	}
+	n, err := strconv.ParseFloat(string(p.input[start:p.pos]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number in expression %s: %w", string(p.input), err)
+	}
+	return literal{value: n}, nil
+}
+
+func (p *expressionParser) parseIdentifier() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' && c != '$' {
+			break
+		}
+		p.pos++
+	}
+	return string(p.input[start:p.pos])
+}
+
+func (p *expressionParser) parseFunction() (expression, error) {
+	name := p.parseIdentifier()
+	p.skipSpaces()
+	if p.peek() != '(' {
+		switch strings.ToLower(name) {
+		case "true":
+			return literal{value: true}, nil
+		case "false":
+			return literal{value: false}, nil
+		case "null":
+			return literal{value: nil}, nil
+		}
+		return nil, fmt.Errorf("expected ( after %s in expression %s", name, string(p.input))
+	}
+	p.pos++
+	var args []expression
+	p.skipSpaces()
+	if p.peek() == ')' {
+		p.pos++
+		return functionCall{name: name, args: args}, nil
+	}
+	for {
+		arg, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		p.skipSpaces()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return functionCall{name: name, args: args}, nil
+		default:
+			return nil, fmt.Errorf("expected , or ) at position %d of expression %s", p.pos, string(p.input))
+		}
+	}
+}
+
+// toString converts a value to its string representation as ARM does
+func toString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+// toNumber converts a value to a number, strings are parsed
+func toNumber(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case int:
+		return float64(t), true
+	case bool:
+		if t {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// toBool converts a value to a boolean
+func toBool(v interface{}) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case string:
+		b, _ := strconv.ParseBool(t)
+		return b
+	case float64:
+		return t != 0
+	}
+	return false
+}
@@ -0,0 +1,608 @@
+package arm
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// function is the implementation of an ARM template function
+type function func(ctx *context, args []interface{}) (interface{}, error)
+
+// functions are the supported ARM template functions keyed by their lower case name.
+// The functions returning runtime values (reference, listKeys, ...) return nil as their value is unknown.
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		"parameters": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("parameters", args, 1); err != nil {
+				return nil, err
+			}
+			return ctx.parameter(toString(args[0]))
+		},
+		"variables": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("variables", args, 1); err != nil {
+				return nil, err
+			}
+			return ctx.variable(toString(args[0]))
+		},
+		"copyindex": func(ctx *context, args []interface{}) (interface{}, error) {
+			var name string
+			var offset float64
+			for _, a := range args {
+				if n, ok := a.(float64); ok {
+					offset = n
+				} else {
+					name = toString(a)
+				}
+			}
+			index, err := ctx.copyIndex(name)
+			if err != nil {
+				return nil, err
+			}
+			return float64(index) + offset, nil
+		},
+		"resourcegroup": func(ctx *context, args []interface{}) (interface{}, error) {
+			return map[string]interface{}{
+				"id":       fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", ctx.opts.SubscriptionID, ctx.opts.ResourceGroup),
+				"name":     ctx.opts.ResourceGroup,
+				"location": ctx.opts.Location,
+			}, nil
+		},
+		"subscription": func(ctx *context, args []interface{}) (interface{}, error) {
+			return map[string]interface{}{
+				"id":             fmt.Sprintf("/subscriptions/%s", ctx.opts.SubscriptionID),
+				"subscriptionId": ctx.opts.SubscriptionID,
+				"tenantId":       "",
+			}, nil
+		},
+		"deployment": func(ctx *context, args []interface{}) (interface{}, error) {
+			return map[string]interface{}{"name": ctx.deployment}, nil
+		},
+		"environment": func(ctx *context, args []interface{}) (interface{}, error) {
+			return map[string]interface{}{"name": "AzureCloud"}, nil
+		},
+		"concat": func(ctx *context, args []interface{}) (interface{}, error) {
+			if len(args) > 0 {
+				if _, ok := args[0].([]interface{}); ok {
+					var result []interface{}
+					for _, a := range args {
+						if l, ok := a.([]interface{}); ok {
+							result = append(result, l...)
+						}
+					}
+					return result, nil
+				}
+			}
+			var sb strings.Builder
+			for _, a := range args {
+				sb.WriteString(toString(a))
+			}
+			return sb.String(), nil
+		},
+		"format": func(ctx *context, args []interface{}) (interface{}, error) {
+			if len(args) == 0 {
+				return nil, fmt.Errorf("format expects at least 1 argument")
+			}
+			return formatRegexp.ReplaceAllStringFunc(toString(args[0]), func(m string) string {
+				var i int
+				fmt.Sscanf(strings.Trim(m, "{}"), "%d", &i)
+				if i+1 < len(args) {
+					return toString(args[i+1])
+				}
+				return m
+			}), nil
+		},
+		"tolower": stringFunction(strings.ToLower),
+		"toupper": stringFunction(strings.ToUpper),
+		"trim":    stringFunction(strings.TrimSpace),
+		"string": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("string", args, 1); err != nil {
+				return nil, err
+			}
+			switch args[0].(type) {
+			case map[string]interface{}, []interface{}:
+				b, err := json.Marshal(args[0])
+				return string(b), err
+			}
+			return toString(args[0]), nil
+		},
+		"int": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("int", args, 1); err != nil {
+				return nil, err
+			}
+			n, ok := toNumber(args[0])
+			if !ok {
+				return nil, fmt.Errorf("can not convert %v to int", args[0])
+			}
+			return math.Trunc(n), nil
+		},
+		"bool": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("bool", args, 1); err != nil {
+				return nil, err
+			}
+			return toBool(args[0]), nil
+		},
+		"json": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("json", args, 1); err != nil {
+				return nil, err
+			}
+			var v interface{}
+			if err := json.Unmarshal([]byte(toString(args[0])), &v); err != nil {
+				return nil, err
+			}
+			return v, nil
+		},
+		"replace": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("replace", args, 3); err != nil {
+				return nil, err
+			}
+			return strings.ReplaceAll(toString(args[0]), toString(args[1]), toString(args[2])), nil
+		},
+		"substring": func(ctx *context, args []interface{}) (interface{}, error) {
+			if len(args) < 2 {
+				return nil, fmt.Errorf("substring expects at least 2 arguments")
+			}
+			s := toString(args[0])
+			start, _ := toNumber(args[1])
+			end := float64(len(s))
+			if len(args) > 2 {
+				length, _ := toNumber(args[2])
+				end = start + length
+			}
+			if start < 0 || end > float64(len(s)) || start > end {
+				return nil, fmt.Errorf("substring out of range")
+			}
+			return s[int(start):int(end)], nil
+		},
+		"split": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("split", args, 2); err != nil {
+				return nil, err
+			}
+			var result []interface{}
+			for _, s := range strings.Split(toString(args[0]), toString(args[1])) {
+				result = append(result, s)
+			}
+			return result, nil
+		},
+		"startswith": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("startsWith", args, 2); err != nil {
+				return nil, err
+			}
+			return strings.HasPrefix(strings.ToLower(toString(args[0])), strings.ToLower(toString(args[1]))), nil
+		},
+		"endswith": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("endsWith", args, 2); err != nil {
+				return nil, err
+			}
+			return strings.HasSuffix(strings.ToLower(toString(args[0])), strings.ToLower(toString(args[1]))), nil
+		},
+		"padleft": func(ctx *context, args []interface{}) (interface{}, error) {
+			if len(args) < 2 {
+				return nil, fmt.Errorf("padLeft expects at least 2 arguments")
+			}
+			s := toString(args[0])
+			width, _ := toNumber(args[1])
+			pad := " "
+			if len(args) > 2 {
+				pad = toString(args[2])
+			}
+			for len(s) < int(width) && pad != "" {
+				s = pad + s
+			}
+			return s, nil
+		},
+		"length": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("length", args, 1); err != nil {
+				return nil, err
+			}
+			switch t := args[0].(type) {
+			case string:
+				return float64(len(t)), nil
+			case []interface{}:
+				return float64(len(t)), nil
+			case map[string]interface{}:
+				return float64(len(t)), nil
+			}
+			return float64(0), nil
+		},
+		"empty": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("empty", args, 1); err != nil {
+				return nil, err
+			}
+			switch t := args[0].(type) {
+			case nil:
+				return true, nil
+			case string:
+				return t == "", nil
+			case []interface{}:
+				return len(t) == 0, nil
+			case map[string]interface{}:
+				return len(t) == 0, nil
+			}
+			return false, nil
+		},
+		"contains": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("contains", args, 2); err != nil {
+				return nil, err
+			}
+			switch t := args[0].(type) {
+			case string:
+				return strings.Contains(t, toString(args[1])), nil
+			case []interface{}:
+				for _, v := range t {
+					if equals(v, args[1]) {
+						return true, nil
+					}
+				}
+			case map[string]interface{}:
+				_, ok := t[toString(args[1])]
+				return ok, nil
+			}
+			return false, nil
+		},
+		"first": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("first", args, 1); err != nil {
+				return nil, err
+			}
+			switch t := args[0].(type) {
+			case string:
+				if t == "" {
+					return "", nil
+				}
+				return t[:1], nil
+			case []interface{}:
+				if len(t) == 0 {
+					return nil, nil
+				}
+				return t[0], nil
+			}
+			return nil, nil
+		},
+		"last": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("last", args, 1); err != nil {
+				return nil, err
+			}
+			switch t := args[0].(type) {
+			case string:
+				if t == "" {
+					return "", nil
+				}
+				return t[len(t)-1:], nil
+			case []interface{}:
+				if len(t) == 0 {
+					return nil, nil
+				}
+				return t[len(t)-1], nil
+			}
+			return nil, nil
+		},
+		"take": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("take", args, 2); err != nil {
+				return nil, err
+			}
+			n, _ := toNumber(args[1])
+			switch t := args[0].(type) {
+			case string:
+				return t[:clamp(int(n), len(t))], nil
+			case []interface{}:
+				return t[:clamp(int(n), len(t))], nil
+			}
+			return nil, nil
+		},
+		"skip": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("skip", args, 2); err != nil {
+				return nil, err
+			}
+			n, _ := toNumber(args[1])
+			switch t := args[0].(type) {
+			case string:
+				return t[clamp(int(n), len(t)):], nil
+			case []interface{}:
+				return t[clamp(int(n), len(t)):], nil
+			}
+			return nil, nil
+		},
+		"createarray": func(ctx *context, args []interface{}) (interface{}, error) {
+			return append([]interface{}{}, args...), nil
+		},
+		"array": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("array", args, 1); err != nil {
+				return nil, err
+			}
+			if l, ok := args[0].([]interface{}); ok {
+				return l, nil
+			}
+			return []interface{}{args[0]}, nil
+		},
+		"createobject": func(ctx *context, args []interface{}) (interface{}, error) {
+			if len(args)%2 != 0 {
+				return nil, fmt.Errorf("createObject expects an even number of arguments")
+			}
+			result := make(map[string]interface{})
+			for i := 0; i < len(args); i += 2 {
+				result[toString(args[i])] = args[i+1]
+			}
+			return result, nil
+		},
+		"union": func(ctx *context, args []interface{}) (interface{}, error) {
+			if len(args) > 0 {
+				if _, ok := args[0].([]interface{}); ok {
+					var result []interface{}
+					for _, a := range args {
+						l, _ := a.([]interface{})
+						for _, v := range l {
+							found := false
+							for _, r := range result {
+								if equals(r, v) {
+									found = true
+									break
+								}
+							}
+							if !found {
+								result = append(result, v)
+							}
+						}
+					}
+					return result, nil
+				}
+			}
+			result := make(map[string]interface{})
+			for _, a := range args {
+				m, _ := a.(map[string]interface{})
+				for k, v := range m {
+					result[k] = v
+				}
+			}
+			return result, nil
+		},
+		"range": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("range", args, 2); err != nil {
+				return nil, err
+			}
+			start, _ := toNumber(args[0])
+			count, _ := toNumber(args[1])
+			var result []interface{}
+			for i := 0; i < int(count); i++ {
+				result = append(result, start+float64(i))
+			}
+			return result, nil
+		},
+		"add": arithmeticFunction(func(a, b float64) float64 { return a + b }),
+		"sub": arithmeticFunction(func(a, b float64) float64 { return a - b }),
+		"mul": arithmeticFunction(func(a, b float64) float64 { return a * b }),
+		"div": arithmeticFunction(func(a, b float64) float64 {
+			if b == 0 {
+				return 0
+			}
+			return math.Trunc(a / b)
+		}),
+		"mod": arithmeticFunction(func(a, b float64) float64 {
+			if b == 0 {
+				return 0
+			}
+			return math.Mod(a, b)
+		}),
+		"min": func(ctx *context, args []interface{}) (interface{}, error) {
+			return aggregate(args, math.Min)
+		},
+		"max": func(ctx *context, args []interface{}) (interface{}, error) {
+			return aggregate(args, math.Max)
+		},
+		"equals": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("equals", args, 2); err != nil {
+				return nil, err
+			}
+			return equals(args[0], args[1]), nil
+		},
+		"less":            comparisonFunction(func(c int) bool { return c < 0 }),
+		"lessorequals":    comparisonFunction(func(c int) bool { return c <= 0 }),
+		"greater":         comparisonFunction(func(c int) bool { return c > 0 }),
+		"greaterorequals": comparisonFunction(func(c int) bool { return c >= 0 }),
+		"not": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("not", args, 1); err != nil {
+				return nil, err
+			}
+			return !toBool(args[0]), nil
+		},
+		"and": func(ctx *context, args []interface{}) (interface{}, error) {
+			for _, a := range args {
+				if !toBool(a) {
+					return false, nil
+				}
+			}
+			return true, nil
+		},
+		"or": func(ctx *context, args []interface{}) (interface{}, error) {
+			for _, a := range args {
+				if toBool(a) {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+		// if is evaluated by functionCall so only the branch taken is evaluated
+		"if": func(ctx *context, args []interface{}) (interface{}, error) {
+			return nil, nil
+		},
+		"coalesce": func(ctx *context, args []interface{}) (interface{}, error) {
+			for _, a := range args {
+				if a != nil {
+					return a, nil
+				}
+			}
+			return nil, nil
+		},
+		"uniquestring": func(ctx *context, args []interface{}) (interface{}, error) {
+			return hashString(args, 13), nil
+		},
+		"guid": func(ctx *context, args []interface{}) (interface{}, error) {
+			h := hashString(args, 32)
+			return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32]), nil
+		},
+		"newguid": func(ctx *context, args []interface{}) (interface{}, error) {
+			return "00000000-0000-0000-0000-000000000000", nil
+		},
+		"utcnow": func(ctx *context, args []interface{}) (interface{}, error) {
+			return "", nil
+		},
+		"base64": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("base64", args, 1); err != nil {
+				return nil, err
+			}
+			return base64.StdEncoding.EncodeToString([]byte(toString(args[0]))), nil
+		},
+		"uri": func(ctx *context, args []interface{}) (interface{}, error) {
+			if err := expectArgs("uri", args, 2); err != nil {
+				return nil, err
+			}
+			return strings.TrimSuffix(toString(args[0]), "/") + "/" + strings.TrimPrefix(toString(args[1]), "/"), nil
+		},
+		"resourceid": func(ctx *context, args []interface{}) (interface{}, error) {
+			var parts []string
+			for _, a := range args {
+				parts = append(parts, toString(a))
+			}
+			return strings.Join(parts, "/"), nil
+		},
+		"subscriptionresourceid": func(ctx *context, args []interface{}) (interface{}, error) {
+			return functions["resourceid"](ctx, args)
+		},
+		"extensionresourceid": func(ctx *context, args []interface{}) (interface{}, error) {
+			return functions["resourceid"](ctx, args)
+		},
+		// Runtime values are unknown before the deployment
+		"reference":   unknownFunction,
+		"listkeys":    unknownFunction,
+		"list":        unknownFunction,
+		"listsecrets": unknownFunction,
+		"pickzones":   unknownFunction,
+	}
+}
+
+var formatRegexp = regexp.MustCompile(`\{\d+(:[^}]*)?\}`)
+
+func unknownFunction(ctx *context, args []interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+func expectArgs(name string, args []interface{}, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("%s expects %d arguments, got %d", name, n, len(args))
+	}
+	return nil
+}
+
+func stringFunction(fn func(string) string) function {
+	return func(ctx *context, args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+		}
+		return fn(toString(args[0])), nil
+	}
+}
+
+func arithmeticFunction(fn func(a, b float64) float64) function {
+	return func(ctx *context, args []interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("expected 2 arguments, got %d", len(args))
+		}
+		a, ok := toNumber(args[0])
+		if !ok {
+			return nil, fmt.Errorf("%v is not a number", args[0])
+		}
+		b, ok := toNumber(args[1])
+		if !ok {
+			return nil, fmt.Errorf("%v is not a number", args[1])
+		}
+		return fn(a, b), nil
+	}
+}
+
+func comparisonFunction(fn func(c int) bool) function {
+	return func(ctx *context, args []interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("expected 2 arguments, got %d", len(args))
+		}
+		a, aok := toNumber(args[0])
+		b, bok := toNumber(args[1])
+		if aok && bok {
+			switch {
+			case a < b:
+				return fn(-1), nil
+			case a > b:
+				return fn(1), nil
+			}
+			return fn(0), nil
+		}
+		return fn(strings.Compare(toString(args[0]), toString(args[1]))), nil
+	}
+}
+
+func aggregate(args []interface{}, fn func(a, b float64) float64) (interface{}, error) {
+	var values []interface{}
+	for _, a := range args {
+		if l, ok := a.([]interface{}); ok {
+			values = append(values, l...)
+		} else {
+			values = append(values, a)
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("expected at least 1 value")
+	}
+	result, _ := toNumber(values[0])
+	for _, v := range values[1:] {
+		n, _ := toNumber(v)
+		result = fn(result, n)
+	}
+	return result, nil
+}
+
+// equals compares two values, strings are compared case-insensitively as ARM does
+func equals(a, b interface{}) bool {
+	if as, ok := a.(string); ok {
+		if bs, ok := b.(string); ok {
+			return strings.EqualFold(as, bs)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// hashString returns a deterministic hash of the values with the given length
+func hashString(args []interface{}, length int) string {
+	var parts []string
+	for _, a := range args {
+		parts = append(parts, toString(a))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "-")))
+	return hex.EncodeToString(sum[:])[:length]
+}
+
+func clamp(n, max int) int {
+	if n < 0 {
+		return 0
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
+
+// sortedKeys returns the keys of the map sorted
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
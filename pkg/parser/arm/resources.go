@@ -0,0 +1,436 @@
+package arm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kaytu-io/pennywise/pkg/diagnostic"
+	azureLocation "github.com/kaytu-io/pennywise/pkg/parser/azurerm/location"
+	"github.com/kaytu-io/pennywise/pkg/parser/properties"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	"github.com/kaytu-io/pennywise/pkg/usage"
+)
+
+// mappedResource is a terraform resource equivalent to (a part of) an ARM resource
+type mappedResource struct {
+	Type   string
+	Name   string
+	Values map[string]interface{}
+}
+
+// resourceMapper returns the terraform resources equivalent to an evaluated ARM resource, some ARM resources
+// define more than one terraform resource (ex: the data disks of a virtual machine). No resources means the
+// resource can not be mapped.
+type resourceMapper func(res properties.Properties) []mappedResource
+
+// resourceMappers are the resourceMapper of each supported ARM resource type keyed by the lower case type
+var resourceMappers = map[string]resourceMapper{
+	"microsoft.compute/virtualmachines":                     virtualMachine,
+	"microsoft.compute/virtualmachinescalesets":             virtualMachineScaleSet,
+	"microsoft.compute/disks":                               managedDisk,
+	"microsoft.compute/snapshots":                           snapshot,
+	"microsoft.compute/images":                              singleResource("azurerm_image", nil),
+	"microsoft.network/loadbalancers":                       loadBalancer,
+	"microsoft.network/publicipaddresses":                   publicIP,
+	"microsoft.network/publicipprefixes":                    publicIPPrefix,
+	"microsoft.network/natgateways":                         natGateway,
+	"microsoft.network/applicationgateways":                 applicationGateway,
+	"microsoft.network/virtualnetworkgateways":              virtualNetworkGateway,
+	"microsoft.network/privateendpoints":                    singleResource("azurerm_private_endpoint", nil),
+	"microsoft.storage/storageaccounts":                     storageAccount,
+	"microsoft.web/serverfarms":                             servicePlan,
+	"microsoft.containerservice/managedclusters":            kubernetesCluster,
+	"microsoft.containerservice/managedclusters/agentpools": kubernetesNodePool,
+	"microsoft.containerregistry/registries":                containerRegistry,
+	"microsoft.sql/servers/databases":                       mssqlDatabase,
+	"microsoft.dbforpostgresql/flexibleservers":             postgresqlFlexibleServer,
+	"microsoft.dbformysql/flexibleservers":                  mysqlFlexibleServer,
+	"microsoft.keyvault/vaults":                             keyVault,
+	"microsoft.network/virtualnetworks":                     singleResource("azurerm_virtual_network", nil),
+	"microsoft.network/virtualnetworks/subnets":             singleResource("azurerm_subnet", nil),
+	"microsoft.network/networkinterfaces":                   singleResource("azurerm_network_interface", nil),
+	"microsoft.network/networksecuritygroups":               singleResource("azurerm_network_security_group", nil),
+	"microsoft.network/routetables":                         singleResource("azurerm_route_table", nil),
+	"microsoft.compute/availabilitysets":                    singleResource("azurerm_availability_set", nil),
+	"microsoft.managedidentity/userassignedidentities":      singleResource("azurerm_user_assigned_identity", nil),
+	"microsoft.authorization/roleassignments":               singleResource("azurerm_role_assignment", nil),
+	"microsoft.sql/servers":                                 singleResource("azurerm_mssql_server", nil),
+}
+
+// ResourceDefs returns the terraform resources equivalent to an ARM resource with its evaluated type, name,
+// location, sku, kind and properties. The addresses are prefixed with the module address. It returns false
+// if the resource type is not supported.
+func ResourceDefs(res map[string]interface{}, moduleAddress string, u usage.Usage) ([]schema.ResourceDef, bool) {
+	mapper, ok := resourceMappers[strings.ToLower(toString(res["type"]))]
+	if !ok {
+		return nil, false
+	}
+	location := properties.Properties(res).StringOr("", "location")
+	regionCode, locationErr := azureLocation.Normalize(location)
+	if locationErr == nil {
+		location = regionCode
+	}
+	var resources []schema.ResourceDef
+	for _, m := range mapper(res) {
+		address := fmt.Sprintf("%s.%s", m.Type, m.Name)
+		if moduleAddress != "" {
+			address = moduleAddress + "." + address
+		}
+		m.Values["location"] = location
+		m.Values[usage.Key] = u.GetUsage(m.Type, address)
+		resource := schema.ResourceDef{
+			Address:      address,
+			Type:         m.Type,
+			Name:         m.Name,
+			RegionCode:   regionCode,
+			ProviderName: schema.AzureProvider,
+			Values:       m.Values,
+		}
+		if locationErr != nil {
+			resource.Diagnostics = []diagnostic.Diagnostic{{Kind: diagnostic.InvalidValue, Attribute: "location", Message: locationErr.Error()}}
+		}
+		resources = append(resources, resource)
+	}
+	return resources, true
+}
+
+// singleResource maps the ARM resource to a single terraform resource with the values returned by values
+func singleResource(rType string, values func(res properties.Properties) map[string]interface{}) resourceMapper {
+	return func(res properties.Properties) []mappedResource {
+		v := map[string]interface{}{}
+		if values != nil {
+			v = values(res)
+		}
+		return []mappedResource{{Type: rType, Name: res.StringOr("", "name"), Values: v}}
+	}
+}
+
+func virtualMachine(res properties.Properties) []mappedResource {
+	storage := res.Object("properties", "storageProfile")
+	osDisk := storage.Object("osDisk")
+	values := map[string]interface{}{
+		"size": res.Value("properties", "hardwareProfile", "vmSize"),
+		"os_disk": []interface{}{map[string]interface{}{
+			"caching":              osDisk.StringOr("None", "caching"),
+			"storage_account_type": osDisk.StringOr("Standard_LRS", "managedDisk", "storageAccountType"),
+			"disk_size_gb":         osDisk.Number("diskSizeGB"),
+		}},
+	}
+	properties.SetValue(values, "license_type", res.Value("properties", "licenseType"))
+	properties.SetValue(values, "priority", res.Value("properties", "priority"))
+	properties.SetValue(values, "eviction_policy", res.Value("properties", "evictionPolicy"))
+	if image := storage.Object("imageReference"); image != nil {
+		values["source_image_reference"] = []interface{}{map[string]interface{}{
+			"publisher": image.Value("publisher"),
+			"offer":     image.Value("offer"),
+			"sku":       image.Value("sku"),
+			"version":   image.StringOr("latest", "version"),
+		}}
+	}
+
+	rType := "azurerm_linux_virtual_machine"
+	if isWindows(res.Object("properties", "osProfile"), storage) {
+		rType = "azurerm_windows_virtual_machine"
+	}
+	name := res.StringOr("", "name")
+	resources := []mappedResource{{Type: rType, Name: name, Values: values}}
+
+	// Empty data disks are created with the virtual machine, attached disks are defined on their own
+	for i, disk := range storage.List("dataDisks") {
+		if !strings.EqualFold(disk.StringOr("Empty", "createOption"), "Empty") {
+			continue
+		}
+		resources = append(resources, mappedResource{
+			Type: "azurerm_managed_disk",
+			Name: disk.StringOr(fmt.Sprintf("%s-datadisk%d", name, i), "name"),
+			Values: map[string]interface{}{
+				"storage_account_type": disk.StringOr("Standard_LRS", "managedDisk", "storageAccountType"),
+				"disk_size_gb":         disk.Number("diskSizeGB"),
+				"create_option":        "Empty",
+			},
+		})
+	}
+	return resources
+}
+
+func virtualMachineScaleSet(res properties.Properties) []mappedResource {
+	profile := res.Object("properties", "virtualMachineProfile")
+	storage := profile.Object("storageProfile")
+	values := map[string]interface{}{
+		"sku":       res.Value("sku", "name"),
+		"instances": res.NumberOr(0, "sku", "capacity"),
+		"os_disk": []interface{}{map[string]interface{}{
+			"caching":              storage.StringOr("None", "osDisk", "caching"),
+			"storage_account_type": storage.StringOr("Standard_LRS", "osDisk", "managedDisk", "storageAccountType"),
+			"disk_size_gb":         storage.Number("osDisk", "diskSizeGB"),
+		}},
+	}
+	properties.SetValue(values, "license_type", profile.Value("licenseType"))
+	properties.SetValue(values, "priority", profile.Value("priority"))
+
+	rType := "azurerm_linux_virtual_machine_scale_set"
+	if isWindows(profile.Object("osProfile"), storage) {
+		rType = "azurerm_windows_virtual_machine_scale_set"
+	}
+	return []mappedResource{{Type: rType, Name: res.StringOr("", "name"), Values: values}}
+}
+
+// isWindows returns true if the virtual machine runs windows based on its os disk, os profile or image
+func isWindows(osProfile, storage properties.Properties) bool {
+	if osType := storage.StringOr("", "osDisk", "osType"); osType != "" {
+		return strings.EqualFold(osType, "Windows")
+	}
+	if osProfile.Value("windowsConfiguration") != nil {
+		return true
+	}
+	if osProfile.Value("linuxConfiguration") != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(storage.StringOr("", "imageReference", "publisher")), "windows")
+}
+
+func managedDisk(res properties.Properties) []mappedResource {
+	values := map[string]interface{}{
+		"storage_account_type": res.StringOr("Standard_LRS", "sku", "name"),
+		"create_option":        res.StringOr("Empty", "properties", "creationData", "createOption"),
+	}
+	properties.SetValue(values, "disk_size_gb", res.Number("properties", "diskSizeGB"))
+	properties.SetValue(values, "disk_iops_read_write", res.Number("properties", "diskIOPSReadWrite"))
+	properties.SetValue(values, "disk_mbps_read_write", res.Number("properties", "diskMBpsReadWrite"))
+	return []mappedResource{{Type: "azurerm_managed_disk", Name: res.StringOr("", "name"), Values: values}}
+}
+
+func snapshot(res properties.Properties) []mappedResource {
+	values := map[string]interface{}{
+		"create_option": res.StringOr("Copy", "properties", "creationData", "createOption"),
+	}
+	properties.SetValue(values, "disk_size_gb", res.Number("properties", "diskSizeGB"))
+	properties.SetValue(values, "incremental_enabled", res.Boolean("properties", "incremental"))
+	return []mappedResource{{Type: "azurerm_snapshot", Name: res.StringOr("", "name"), Values: values}}
+}
+
+func loadBalancer(res properties.Properties) []mappedResource {
+	name := res.StringOr("", "name")
+	resources := []mappedResource{{
+		Type: "azurerm_lb",
+		Name: name,
+		Values: map[string]interface{}{
+			"sku":      res.StringOr("Basic", "sku", "name"),
+			"sku_tier": res.StringOr("Regional", "sku", "tier"),
+		},
+	}}
+	// The rules are billed for the standard load balancers
+	for _, rule := range res.List("properties", "loadBalancingRules") {
+		resources = append(resources, mappedResource{
+			Type:   "azurerm_lb_rule",
+			Name:   name + "/" + rule.StringOr("", "name"),
+			Values: map[string]interface{}{"name": rule.Value("name"), "loadbalancer_id": name},
+		})
+	}
+	for _, rule := range res.List("properties", "outboundRules") {
+		resources = append(resources, mappedResource{
+			Type:   "azurerm_lb_outbound_rule",
+			Name:   name + "/" + rule.StringOr("", "name"),
+			Values: map[string]interface{}{"name": rule.Value("name"), "loadbalancer_id": name},
+		})
+	}
+	return resources
+}
+
+func publicIP(res properties.Properties) []mappedResource {
+	sku := res.StringOr("Basic", "sku", "name")
+	allocation := "Dynamic"
+	if strings.EqualFold(sku, "Standard") {
+		allocation = "Static"
+	}
+	return []mappedResource{{
+		Type: "azurerm_public_ip",
+		Name: res.StringOr("", "name"),
+		Values: map[string]interface{}{
+			"sku":               sku,
+			"sku_tier":          res.StringOr("Regional", "sku", "tier"),
+			"allocation_method": res.StringOr(allocation, "properties", "publicIPAllocationMethod"),
+		},
+	}}
+}
+
+func publicIPPrefix(res properties.Properties) []mappedResource {
+	values := map[string]interface{}{
+		"sku": res.StringOr("Standard", "sku", "name"),
+	}
+	properties.SetValue(values, "prefix_length", res.Number("properties", "prefixLength"))
+	return []mappedResource{{Type: "azurerm_public_ip_prefix", Name: res.StringOr("", "name"), Values: values}}
+}
+
+func natGateway(res properties.Properties) []mappedResource {
+	return []mappedResource{{
+		Type:   "azurerm_nat_gateway",
+		Name:   res.StringOr("", "name"),
+		Values: map[string]interface{}{"sku_name": res.StringOr("Standard", "sku", "name")},
+	}}
+}
+
+func applicationGateway(res properties.Properties) []mappedResource {
+	sku := res.Object("properties", "sku")
+	values := map[string]interface{}{
+		"sku": []interface{}{map[string]interface{}{
+			"name":     sku.Value("name"),
+			"tier":     sku.Value("tier"),
+			"capacity": sku.Number("capacity"),
+		}},
+	}
+	if autoscale := res.Object("properties", "autoscaleConfiguration"); autoscale != nil {
+		values["autoscale_configuration"] = []interface{}{map[string]interface{}{
+			"min_capacity": autoscale.Number("minCapacity"),
+			"max_capacity": autoscale.Number("maxCapacity"),
+		}}
+	}
+	return []mappedResource{{Type: "azurerm_application_gateway", Name: res.StringOr("", "name"), Values: values}}
+}
+
+func virtualNetworkGateway(res properties.Properties) []mappedResource {
+	return []mappedResource{{
+		Type: "azurerm_virtual_network_gateway",
+		Name: res.StringOr("", "name"),
+		Values: map[string]interface{}{
+			"sku":      res.Value("properties", "sku", "name"),
+			"type":     res.StringOr("Vpn", "properties", "gatewayType"),
+			"vpn_type": res.StringOr("RouteBased", "properties", "vpnType"),
+		},
+	}}
+}
+
+func storageAccount(res properties.Properties) []mappedResource {
+	// The sku name is the tier and the replication type, ex: Standard_LRS
+	tier, replication, _ := strings.Cut(res.StringOr("Standard_LRS", "sku", "name"), "_")
+	values := map[string]interface{}{
+		"account_kind":             res.StringOr("StorageV2", "kind"),
+		"account_tier":             tier,
+		"account_replication_type": replication,
+		"access_tier":              res.StringOr("Hot", "properties", "accessTier"),
+	}
+	properties.SetValue(values, "is_hns_enabled", res.Boolean("properties", "isHnsEnabled"))
+	return []mappedResource{{Type: "azurerm_storage_account", Name: res.StringOr("", "name"), Values: values}}
+}
+
+func servicePlan(res properties.Properties) []mappedResource {
+	osType := "Windows"
+	if strings.Contains(strings.ToLower(res.StringOr("", "kind")), "linux") || res.Boolean("properties", "reserved") == true {
+		osType = "Linux"
+	}
+	return []mappedResource{{
+		Type: "azurerm_service_plan",
+		Name: res.StringOr("", "name"),
+		Values: map[string]interface{}{
+			"sku_name":     res.Value("sku", "name"),
+			"os_type":      osType,
+			"worker_count": res.NumberOr(1, "sku", "capacity"),
+		},
+	}}
+}
+
+func kubernetesCluster(res properties.Properties) []mappedResource {
+	name := res.StringOr("", "name")
+	pools := res.List("properties", "agentPoolProfiles")
+	values := map[string]interface{}{
+		"sku_tier": res.StringOr("Free", "sku", "tier"),
+	}
+	if len(pools) > 0 {
+		values["default_node_pool"] = []interface{}{nodePoolValues(pools[0])}
+	}
+	resources := []mappedResource{{Type: "azurerm_kubernetes_cluster", Name: name, Values: values}}
+	for _, pool := range pools[min(1, len(pools)):] {
+		resources = append(resources, mappedResource{
+			Type:   "azurerm_kubernetes_cluster_node_pool",
+			Name:   name + "/" + pool.StringOr("", "name"),
+			Values: nodePoolValues(pool),
+		})
+	}
+	return resources
+}
+
+func kubernetesNodePool(res properties.Properties) []mappedResource {
+	values := nodePoolValues(res.Object("properties"))
+	return []mappedResource{{Type: "azurerm_kubernetes_cluster_node_pool", Name: res.StringOr("", "name"), Values: values}}
+}
+
+func nodePoolValues(pool properties.Properties) map[string]interface{} {
+	values := map[string]interface{}{
+		"name":       pool.Value("name"),
+		"vm_size":    pool.Value("vmSize"),
+		"node_count": pool.NumberOr(1, "count"),
+		"os_type":    pool.StringOr("Linux", "osType"),
+	}
+	properties.SetValue(values, "os_disk_size_gb", pool.Number("osDiskSizeGB"))
+	properties.SetValue(values, "os_disk_type", pool.Value("osDiskType"))
+	properties.SetValue(values, "min_count", pool.Number("minCount"))
+	properties.SetValue(values, "max_count", pool.Number("maxCount"))
+	properties.SetValue(values, "priority", pool.Value("scaleSetPriority"))
+	return values
+}
+
+func containerRegistry(res properties.Properties) []mappedResource {
+	return []mappedResource{{
+		Type:   "azurerm_container_registry",
+		Name:   res.StringOr("", "name"),
+		Values: map[string]interface{}{"sku": res.StringOr("Basic", "sku", "name")},
+	}}
+}
+
+func mssqlDatabase(res properties.Properties) []mappedResource {
+	values := map[string]interface{}{
+		"sku_name": res.StringOr("GP_S_Gen5_2", "sku", "name"),
+	}
+	if size := res.Number("properties", "maxSizeBytes"); size != nil {
+		values["max_size_gb"] = size.(float64) / (1024 * 1024 * 1024)
+	}
+	properties.SetValue(values, "zone_redundant", res.Boolean("properties", "zoneRedundant"))
+	properties.SetValue(values, "license_type", res.Value("properties", "licenseType"))
+	properties.SetValue(values, "read_replica_count", res.Number("properties", "highAvailabilityReplicaCount"))
+	return []mappedResource{{Type: "azurerm_mssql_database", Name: res.StringOr("", "name"), Values: values}}
+}
+
+// flexibleServerSku returns the terraform sku name of a flexible server, ex: GP_Standard_D2s_v3
+func flexibleServerSku(res properties.Properties) string {
+	prefixes := map[string]string{"burstable": "B", "generalpurpose": "GP", "memoryoptimized": "MO"}
+	name := res.StringOr("", "sku", "name")
+	if prefix, ok := prefixes[strings.ToLower(res.StringOr("", "sku", "tier"))]; ok && name != "" {
+		return prefix + "_" + name
+	}
+	return name
+}
+
+func postgresqlFlexibleServer(res properties.Properties) []mappedResource {
+	values := map[string]interface{}{
+		"sku_name": flexibleServerSku(res),
+	}
+	if size := res.Number("properties", "storage", "storageSizeGB"); size != nil {
+		values["storage_mb"] = size.(float64) * 1024
+	}
+	if mode := res.StringOr("", "properties", "highAvailability", "mode"); mode != "" && !strings.EqualFold(mode, "Disabled") {
+		values["high_availability"] = []interface{}{map[string]interface{}{"mode": mode}}
+	}
+	return []mappedResource{{Type: "azurerm_postgresql_flexible_server", Name: res.StringOr("", "name"), Values: values}}
+}
+
+func mysqlFlexibleServer(res properties.Properties) []mappedResource {
+	values := map[string]interface{}{
+		"sku_name": flexibleServerSku(res),
+	}
+	storage := map[string]interface{}{}
+	properties.SetValue(storage, "size_gb", res.Number("properties", "storage", "storageSizeGB"))
+	properties.SetValue(storage, "iops", res.Number("properties", "storage", "iops"))
+	values["storage"] = []interface{}{storage}
+	if mode := res.StringOr("", "properties", "highAvailability", "mode"); mode != "" && !strings.EqualFold(mode, "Disabled") {
+		values["high_availability"] = []interface{}{map[string]interface{}{"mode": mode}}
+	}
+	return []mappedResource{{Type: "azurerm_mysql_flexible_server", Name: res.StringOr("", "name"), Values: values}}
+}
+
+func keyVault(res properties.Properties) []mappedResource {
+	return []mappedResource{{
+		Type:   "azurerm_key_vault",
+		Name:   res.StringOr("", "name"),
+		Values: map[string]interface{}{"sku_name": strings.ToLower(res.StringOr("standard", "properties", "sku", "name"))},
+	}}
+}
@@ -0,0 +1,381 @@
+package arm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Template is an Azure Resource Manager template, templates compiled from bicep files are ARM templates as well
+type Template struct {
+	LanguageVersion string                     `json:"languageVersion"`
+	Parameters      map[string]Parameter       `json:"parameters"`
+	Variables       map[string]interface{}     `json:"variables"`
+	Resources       json.RawMessage            `json:"resources"`
+	Outputs         map[string]json.RawMessage `json:"outputs"`
+}
+
+// Parameter is the definition of a template parameter
+type Parameter struct {
+	Type         string      `json:"type"`
+	DefaultValue interface{} `json:"defaultValue"`
+}
+
+// Resource is a resource of the template before its expressions are evaluated
+type Resource struct {
+	Type      string                 `json:"type"`
+	Name      string                 `json:"name"`
+	Condition interface{}            `json:"condition"`
+	Existing  bool                   `json:"existing"`
+	Copy      *Copy                  `json:"copy"`
+	Resources []Resource             `json:"resources"`
+	Raw       map[string]interface{} `json:"-"`
+}
+
+// Copy is the copy loop of a resource, a property or a variable
+type Copy struct {
+	Name  string      `json:"name"`
+	Count interface{} `json:"count"`
+	Input interface{} `json:"input"`
+}
+
+// Options are the options used to evaluate a template
+type Options struct {
+	// Location is the location of the resource group the template is deployed to, it's the value of resourceGroup().location
+	Location string
+	// ResourceGroup is the name of the resource group
+	ResourceGroup string
+	// SubscriptionID is the subscription the template is deployed to
+	SubscriptionID string
+	// DeploymentName is the value of deployment().name
+	DeploymentName string
+}
+
+// ReadTemplate reads the ARM template on the path
+func ReadTemplate(path string) (*Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", path, err)
+	}
+	var template Template
+	if err := json.Unmarshal(content, &template); err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+	return &template, nil
+}
+
+// ReadParameters reads a parameters file ({"parameters": {"name": {"value": ...}}}), a plain
+// json object of parameter values is accepted as well
+func ReadParameters(path string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parameters file %s: %w", path, err)
+	}
+	var file map[string]interface{}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse parameters file %s: %w", path, err)
+	}
+	if p, ok := file["parameters"].(map[string]interface{}); ok {
+		file = p
+	}
+	parameters := make(map[string]interface{})
+	for name, p := range file {
+		if m, ok := p.(map[string]interface{}); ok {
+			if v, ok := m["value"]; ok {
+				parameters[name] = v
+				continue
+			}
+			// Key vault references are unknown
+			if _, ok := m["reference"]; ok {
+				parameters[name] = nil
+				continue
+			}
+		}
+		parameters[name] = p
+	}
+	return parameters, nil
+}
+
+// resources returns the resources of the template, resources is an array in the templates
+// and an object of symbolic names in the language version 2.0
+func (t *Template) resources() ([]Resource, error) {
+	if len(t.Resources) == 0 {
+		return nil, nil
+	}
+	var raws []map[string]interface{}
+	if strings.HasPrefix(strings.TrimSpace(string(t.Resources)), "{") {
+		var symbolic map[string]map[string]interface{}
+		if err := json.Unmarshal(t.Resources, &symbolic); err != nil {
+			return nil, fmt.Errorf("failed to parse resources: %w", err)
+		}
+		for _, name := range sortedKeys(symbolic) {
+			raws = append(raws, symbolic[name])
+		}
+	} else if err := json.Unmarshal(t.Resources, &raws); err != nil {
+		return nil, fmt.Errorf("failed to parse resources: %w", err)
+	}
+
+	var resources []Resource
+	for _, raw := range raws {
+		r, err := newResource(raw)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, *r)
+	}
+	return resources, nil
+}
+
+func newResource(raw map[string]interface{}) (*Resource, error) {
+	content, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var r Resource
+	if err := json.Unmarshal(content, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse resource: %w", err)
+	}
+	r.Raw = raw
+	r.Resources = nil
+	if children, ok := raw["resources"].([]interface{}); ok {
+		for _, c := range children {
+			m, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			child, err := newResource(m)
+			if err != nil {
+				return nil, err
+			}
+			r.Resources = append(r.Resources, *child)
+		}
+	}
+	return &r, nil
+}
+
+// context is the evaluation context of the expressions of a template
+type context struct {
+	opts       Options
+	deployment string
+	template   *Template
+	parameters map[string]interface{}
+	variables  map[string]interface{}
+	// evaluating are the parameters and variables being evaluated, to detect the ones referencing themselves
+	evaluating map[string]bool
+	// copyIndexes are the indexes of the copy loops being evaluated, the innermost loop is the last one
+	copyIndexes []copyIndex
+}
+
+type copyIndex struct {
+	name  string
+	index int
+}
+
+func newContext(template *Template, parameters map[string]interface{}, opts Options, deployment string) (*context, error) {
+	ctx := &context{
+		opts:       opts,
+		deployment: deployment,
+		template:   template,
+		parameters: make(map[string]interface{}),
+		variables:  make(map[string]interface{}),
+		evaluating: make(map[string]bool),
+	}
+	for name := range template.Parameters {
+		if v, ok := lookup(parameters, name); ok {
+			ctx.parameters[name] = v
+		}
+	}
+	for _, name := range sortedKeys(template.Parameters) {
+		if _, err := ctx.parameter(name); err != nil {
+			return nil, err
+		}
+	}
+	return ctx, nil
+}
+
+// parameter evaluates the default value of the parameter the first time it's used so default values can reference
+// other parameters
+func (ctx *context) parameter(name string) (interface{}, error) {
+	if v, ok := lookup(ctx.parameters, name); ok {
+		return v, nil
+	}
+	p, ok := lookup(ctx.template.Parameters, name)
+	if !ok {
+		return nil, fmt.Errorf("parameter %s is not defined", name)
+	}
+	key := "parameters/" + strings.ToLower(name)
+	if ctx.evaluating[key] {
+		return nil, fmt.Errorf("parameter %s references itself", name)
+	}
+	ctx.evaluating[key] = true
+	defer delete(ctx.evaluating, key)
+
+	v, err := ctx.evaluate(p.DefaultValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate the default value of parameter %s: %w", name, err)
+	}
+	ctx.parameters[name] = v
+	return v, nil
+}
+
+// variable evaluates the variable the first time it's used so variables can reference each other
+func (ctx *context) variable(name string) (interface{}, error) {
+	if v, ok := lookup(ctx.variables, name); ok {
+		return v, nil
+	}
+	key := "variables/" + strings.ToLower(name)
+	if ctx.evaluating[key] {
+		return nil, fmt.Errorf("variable %s references itself", name)
+	}
+	ctx.evaluating[key] = true
+	defer delete(ctx.evaluating, key)
+
+	// Variables defined with a copy loop
+	if copies, ok := ctx.template.Variables["copy"].([]interface{}); ok {
+		for _, c := range copies {
+			m, _ := c.(map[string]interface{})
+			if !strings.EqualFold(toString(m["name"]), name) {
+				continue
+			}
+			v, err := ctx.evaluateCopy(m)
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate variable %s: %w", name, err)
+			}
+			ctx.variables[name] = v
+			return v, nil
+		}
+	}
+
+	raw, ok := lookup(ctx.template.Variables, name)
+	if !ok {
+		return nil, fmt.Errorf("variable %s is not defined", name)
+	}
+	v, err := ctx.evaluate(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate variable %s: %w", name, err)
+	}
+	ctx.variables[name] = v
+	return v, nil
+}
+
+func (ctx *context) copyIndex(name string) (int, error) {
+	for i := len(ctx.copyIndexes) - 1; i >= 0; i-- {
+		if name == "" || strings.EqualFold(ctx.copyIndexes[i].name, name) {
+			return ctx.copyIndexes[i].index, nil
+		}
+	}
+	if name == "" {
+		return 0, fmt.Errorf("copyIndex used outside of a copy loop")
+	}
+	return 0, fmt.Errorf("copy loop %s not found", name)
+}
+
+func (ctx *context) withCopyIndex(name string, index int, fn func() error) error {
+	ctx.copyIndexes = append(ctx.copyIndexes, copyIndex{name: name, index: index})
+	defer func() { ctx.copyIndexes = ctx.copyIndexes[:len(ctx.copyIndexes)-1] }()
+	return fn()
+}
+
+// evaluate evaluates all the expressions of the value, the property copy loops of objects are expanded
+func (ctx *context) evaluate(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, "[[") {
+			return v[1:], nil
+		}
+		if !isExpression(v) {
+			return v, nil
+		}
+		expr, err := parseExpression(v)
+		if err != nil {
+			return nil, err
+		}
+		return expr.eval(ctx)
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			e, err := ctx.evaluate(item)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, e)
+		}
+		return result, nil
+	case map[string]interface{}:
+		result := make(map[string]interface{})
+		for _, k := range sortedKeys(v) {
+			if k == "copy" {
+				copies, ok := v[k].([]interface{})
+				if ok {
+					for _, c := range copies {
+						m, _ := c.(map[string]interface{})
+						items, err := ctx.evaluateCopy(m)
+						if err != nil {
+							return nil, err
+						}
+						result[toString(m["name"])] = items
+					}
+					continue
+				}
+			}
+			e, err := ctx.evaluate(v[k])
+			if err != nil {
+				return nil, err
+			}
+			result[k] = e
+		}
+		return result, nil
+	}
+	return value, nil
+}
+
+// evaluateCopy evaluates a property or variable copy loop, the input is evaluated count times
+func (ctx *context) evaluateCopy(c map[string]interface{}) ([]interface{}, error) {
+	name := toString(c["name"])
+	count, err := ctx.evaluateCount(c["count"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate the count of copy %s: %w", name, err)
+	}
+	result := make([]interface{}, 0, count)
+	for i := 0; i < count; i++ {
+		err := ctx.withCopyIndex(name, i, func() error {
+			v, err := ctx.evaluate(c["input"])
+			if err != nil {
+				return err
+			}
+			result = append(result, v)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (ctx *context) evaluateCount(count interface{}) (int, error) {
+	v, err := ctx.evaluate(count)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := toNumber(v)
+	if !ok {
+		return 0, fmt.Errorf("count %v is not a number", v)
+	}
+	return int(n), nil
+}
+
+// lookup returns the value of the key, template names are case-insensitive
+func lookup[T any](m map[string]T, name string) (T, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -3,6 +3,7 @@ package aws
 import (
 	"fmt"
 	"github.com/kaytu-io/pennywise/pkg/parser/aws/region"
+	"os"
 )
 
 // Provider is an implementation of the terraform.Provider, used to extract component queries from
@@ -13,10 +14,21 @@ type Provider struct {
 }
 
 // NewProvider returns a new Provider with the provided default region and a query key.
+// Regions that are not known yet are only warned about, as they may be newer than the regions list, as are the
+// regions outside of the aws partition and the opt-in regions.
 func NewProvider(key string, regionCode region.Code) (*Provider, error) {
-	if !regionCode.Valid() {
+	if regionCode == "" {
 		return nil, fmt.Errorf("invalid AWS region: %q", regionCode)
 	}
+	r, ok := regionCode.Region()
+	switch {
+	case !ok:
+		fmt.Fprintf(os.Stderr, "warning: unknown AWS region %q, its resources may not be priced\n", regionCode)
+	case r.Partition != "aws":
+		fmt.Fprintf(os.Stderr, "warning: AWS region %q is in the %s partition, its resources may not be priced\n", regionCode, r.Partition)
+	case r.OptIn:
+		fmt.Fprintf(os.Stderr, "warning: AWS region %q is an opt-in region, it has to be enabled on the account\n", regionCode)
+	}
 	return &Provider{key: key, region: regionCode}, nil
 }
 
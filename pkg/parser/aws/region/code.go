@@ -8,10 +8,16 @@ func (c Code) Valid() bool {
 	if c == "" {
 		return false
 	}
-	_, ok := codeToName[c]
+	_, ok := regions[c]
 	return ok
 }
 
+// Region returns the region of the code, false if the region is not known
+func (c Code) Region() (Region, bool) {
+	r, ok := regions[c]
+	return r, ok
+}
+
 // String returns the code of the region as a string.
 func (c Code) String() string {
 	return string(c)
@@ -0,0 +1,110 @@
+//go:build ignore
+
+// gen regenerates regions.json from the endpoints of botocore, which lists the regions of every partition.
+// The opt-in status is not part of the endpoints so it's kept from the current regions.json, the regions
+// that are new are opt-in as every region launched since 2019. The regions of the current file missing
+// from the endpoints (ex: local zones of the offer files) are kept.
+//
+//	go generate ./pkg/parser/aws/region
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+const endpointsURL = "https://raw.githubusercontent.com/boto/botocore/develop/botocore/data/endpoints.json"
+
+const regionsFile = "regions.json"
+
+type region struct {
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	Partition string `json:"partition"`
+	OptIn     bool   `json:"opt_in"`
+}
+
+type endpoints struct {
+	Partitions []struct {
+		Partition string `json:"partition"`
+		Regions   map[string]struct {
+			Description string `json:"description"`
+		} `json:"regions"`
+	} `json:"partitions"`
+}
+
+func main() {
+	if err := generate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func generate() error {
+	current := make(map[string]region)
+	if content, err := os.ReadFile(regionsFile); err == nil {
+		var list []region
+		if err := json.Unmarshal(content, &list); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", regionsFile, err)
+		}
+		for _, r := range list {
+			current[r.Code] = r
+		}
+	}
+
+	resp, err := http.Get(endpointsURL)
+	if err != nil {
+		return fmt.Errorf("failed to get the endpoints: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get the endpoints: %s", resp.Status)
+	}
+	var e endpoints
+	if err := json.NewDecoder(resp.Body).Decode(&e); err != nil {
+		return fmt.Errorf("failed to parse the endpoints: %w", err)
+	}
+
+	regions := make(map[string]region)
+	for code, r := range current {
+		regions[code] = r
+	}
+	for _, p := range e.Partitions {
+		for code, r := range p.Regions {
+			optIn := p.Partition == "aws"
+			if c, ok := current[code]; ok {
+				optIn = c.OptIn
+			}
+			regions[code] = region{
+				Code: code,
+				// The offer files use the former names of the european regions
+				Name:      strings.Replace(r.Description, "Europe (", "EU (", 1),
+				Partition: p.Partition,
+				OptIn:     optIn,
+			}
+		}
+	}
+
+	list := make([]region, 0, len(regions))
+	for _, r := range regions {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Partition != list[j].Partition {
+			if list[i].Partition == "aws" || list[j].Partition == "aws" {
+				return list[i].Partition == "aws"
+			}
+			return list[i].Partition < list[j].Partition
+		}
+		return list[i].Code < list[j].Code
+	})
+	content, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(regionsFile, append(content, '\n'), 0644)
+}
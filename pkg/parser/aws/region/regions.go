@@ -1,39 +1,37 @@
 package region
 
-// List of regions with their codes can be found here: https://docs.aws.amazon.com/general/latest/gr/ec2-service.html
-var nameToCode = map[string]Code{
-	"US East (N. Virginia)":      "us-east-1",
-	"US East (Ohio)":             "us-east-2",
-	"US West (N. California)":    "us-west-1",
-	"US West (Oregon)":           "us-west-2",
-	"US West (Los Angeles)":      "us-west-2-lax-1", // Included in the offer file but missing from the docs.
-	"Canada (Central)":           "ca-central-1",
-	"EU (Stockholm)":             "eu-north-1",
-	"EU (Ireland)":               "eu-west-1",
-	"EU (London)":                "eu-west-2",
-	"EU (Paris)":                 "eu-west-3",
-	"EU (Frankfurt)":             "eu-central-1",
-	"EU (Milan)":                 "eu-south-1",
-	"Asia Pacific (Mumbai)":      "ap-south-1",
-	"Asia Pacific (Tokyo)":       "ap-northeast-1",
-	"Asia Pacific (Seoul)":       "ap-northeast-2",
-	"Asia Pacific (Osaka-Local)": "ap-northeast-3",
-	"Asia Pacific (Singapore)":   "ap-southeast-1",
-	"Asia Pacific (Sydney)":      "ap-southeast-2",
-	"Asia Pacific (Hong Kong)":   "ap-east-1",
-	"South America (Sao Paulo)":  "sa-east-1",
-	"China (Beijing)":            "cn-north-1",
-	"China (Ningxia)":            "cn-northwest-1",
-	"AWS GovCloud (US-West)":     "us-gov-west-1",
-	"AWS GovCloud (US-East)":     "us-gov-east-1",
-	"Middle East (Bahrain)":      "me-south-1",
-	"Africa (Cape Town)":         "af-south-1",
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+//go:generate go run gen.go
+
+// regionsJSON is the list of regions with their names (as used by the pricing offer files), partitions and
+// opt-in status. It's regenerated from the botocore endpoints with go generate, see gen.go.
+//
+//go:embed regions.json
+var regionsJSON []byte
+
+// Region is an AWS region
+type Region struct {
+	Code Code `json:"code"`
+	// Name is the location name of the region on the pricing offer files, ex: EU (Ireland)
+	Name string `json:"name"`
+	// Partition is the partition the region belongs to, ex: aws, aws-cn or aws-us-gov
+	Partition string `json:"partition"`
+	// OptIn is true if the region has to be enabled on the account before being used
+	OptIn bool `json:"opt_in"`
 }
 
-var codeToName = make(map[Code]string)
+var regions = make(map[Code]Region)
 
 func init() {
-	for name, code := range nameToCode {
-		codeToName[code] = name
+	var list []Region
+	if err := json.Unmarshal(regionsJSON, &list); err != nil {
+		panic(err)
+	}
+	for _, r := range list {
+		regions[r.Code] = r
 	}
 }
@@ -9,6 +9,9 @@ const (
 	// RegistryName is the fully qualified name under which this provider is stored in the registry.
 	RegistryName = "registry.terraform.io/hashicorp/aws"
 
+	// OpenTofuRegistryName is the fully qualified name under which this provider is stored in the OpenTofu registry.
+	OpenTofuRegistryName = "registry.opentofu.org/hashicorp/aws"
+
 	// DefaultRegion is the region used by default when none is defined on the provider
 	DefaultRegion = "us-east-1"
 
@@ -17,7 +20,7 @@ const (
 
 // TerraformProviderInitializer is a terraform.ProviderInitializer that initializes the default AWS provider.
 var TerraformProviderInitializer = terraform.ProviderInitializer{
-	MatchNames: []string{ProviderName, RegistryName},
+	MatchNames: []string{ProviderName, RegistryName, OpenTofuRegistryName},
 	Provider: func(values map[string]interface{}) (terraform.Provider, error) {
 		r, ok := values["region"]
 		// If no region is defined it means it was passed via ENV variables
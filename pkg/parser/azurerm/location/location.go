@@ -0,0 +1,92 @@
+package location
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/kaytu-io/pennywise/pkg/suggest"
+)
+
+// Cloud is an Azure cloud (environment)
+type Cloud string
+
+const (
+	PublicCloud       Cloud = "AzureCloud"
+	USGovernmentCloud Cloud = "AzureUSGovernment"
+	ChinaCloud        Cloud = "AzureChinaCloud"
+)
+
+// Global is the location of the resources that are not deployed to a location
+const Global = "global"
+
+// maxSuggestions is the maximum number of locations suggested for an unknown location
+const maxSuggestions = 3
+
+// Location is an Azure location
+type Location struct {
+	// Name is the name of the location used by the APIs, ex: westeurope
+	Name string
+	// DisplayName is the name of the location shown on the portal, ex: West Europe
+	DisplayName string
+	Cloud       Cloud
+}
+
+// byKey indexes the locations by the key of their name and display name
+var byKey = make(map[string]Location)
+
+func init() {
+	for _, l := range locations {
+		byKey[key(l.Name)] = l
+		byKey[key(l.DisplayName)] = l
+	}
+}
+
+// Lookup returns the location of the name, the name can be the name or the display name of the location
+// in any case and with or without spaces, dashes or underscores (ex: westeurope, West Europe or west-europe)
+func Lookup(name string) (Location, bool) {
+	l, ok := byKey[key(name)]
+	return l, ok
+}
+
+// Normalize returns the name of the location used by the APIs (ex: westeurope for West Europe), the
+// error suggests the closest locations if the location is not known
+func Normalize(name string) (string, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", fmt.Errorf("no Azure location given")
+	}
+	if strings.EqualFold(strings.TrimSpace(name), Global) {
+		return Global, nil
+	}
+	if l, ok := Lookup(name); ok {
+		return l.Name, nil
+	}
+	if suggestions := closest(name); len(suggestions) > 0 {
+		return "", fmt.Errorf("unknown Azure location %q, did you mean %s?", name, strings.Join(suggestions, ", "))
+	}
+	return "", fmt.Errorf("unknown Azure location %q", name)
+}
+
+// closest returns the names of the locations closest to the name
+func closest(name string) []string {
+	keys := make([]string, 0, len(locations))
+	for _, l := range locations {
+		keys = append(keys, key(l.Name))
+	}
+	var suggestions []string
+	for _, k := range suggest.Closest(key(name), keys, maxSuggestions) {
+		suggestions = append(suggestions, byKey[k].Name)
+	}
+	return suggestions
+}
+
+// key returns the name lower cased without the characters that are not letters or digits
+func key(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package location
+
+// locations are the locations of the Azure clouds, the list of the public cloud can be found with az account list-locations
+var locations = []Location{
+	// Public cloud
+	{"eastus", "East US", PublicCloud},
+	{"eastus2", "East US 2", PublicCloud},
+	{"southcentralus", "South Central US", PublicCloud},
+	{"westus2", "West US 2", PublicCloud},
+	{"westus3", "West US 3", PublicCloud},
+	{"australiaeast", "Australia East", PublicCloud},
+	{"southeastasia", "Southeast Asia", PublicCloud},
+	{"northeurope", "North Europe", PublicCloud},
+	{"swedencentral", "Sweden Central", PublicCloud},
+	{"uksouth", "UK South", PublicCloud},
+	{"westeurope", "West Europe", PublicCloud},
+	{"centralus", "Central US", PublicCloud},
+	{"southafricanorth", "South Africa North", PublicCloud},
+	{"centralindia", "Central India", PublicCloud},
+	{"eastasia", "East Asia", PublicCloud},
+	{"japaneast", "Japan East", PublicCloud},
+	{"koreacentral", "Korea Central", PublicCloud},
+	{"newzealandnorth", "New Zealand North", PublicCloud},
+	{"canadacentral", "Canada Central", PublicCloud},
+	{"francecentral", "France Central", PublicCloud},
+	{"germanywestcentral", "Germany West Central", PublicCloud},
+	{"italynorth", "Italy North", PublicCloud},
+	{"norwayeast", "Norway East", PublicCloud},
+	{"polandcentral", "Poland Central", PublicCloud},
+	{"spaincentral", "Spain Central", PublicCloud},
+	{"switzerlandnorth", "Switzerland North", PublicCloud},
+	{"mexicocentral", "Mexico Central", PublicCloud},
+	{"uaenorth", "UAE North", PublicCloud},
+	{"brazilsouth", "Brazil South", PublicCloud},
+	{"israelcentral", "Israel Central", PublicCloud},
+	{"qatarcentral", "Qatar Central", PublicCloud},
+	{"indonesiacentral", "Indonesia Central", PublicCloud},
+	{"malaysiawest", "Malaysia West", PublicCloud},
+	{"chilecentral", "Chile Central", PublicCloud},
+	{"northcentralus", "North Central US", PublicCloud},
+	{"westus", "West US", PublicCloud},
+	{"japanwest", "Japan West", PublicCloud},
+	{"jioindiawest", "Jio India West", PublicCloud},
+	{"jioindiacentral", "Jio India Central", PublicCloud},
+	{"koreasouth", "Korea South", PublicCloud},
+	{"southindia", "South India", PublicCloud},
+	{"westindia", "West India", PublicCloud},
+	{"canadaeast", "Canada East", PublicCloud},
+	{"francesouth", "France South", PublicCloud},
+	{"germanynorth", "Germany North", PublicCloud},
+	{"norwaywest", "Norway West", PublicCloud},
+	{"switzerlandwest", "Switzerland West", PublicCloud},
+	{"ukwest", "UK West", PublicCloud},
+	{"uaecentral", "UAE Central", PublicCloud},
+	{"brazilsoutheast", "Brazil Southeast", PublicCloud},
+	{"brazilus", "Brazil US", PublicCloud},
+	{"australiacentral", "Australia Central", PublicCloud},
+	{"australiacentral2", "Australia Central 2", PublicCloud},
+	{"australiasoutheast", "Australia Southeast", PublicCloud},
+	{"southafricawest", "South Africa West", PublicCloud},
+	{"westcentralus", "West Central US", PublicCloud},
+	{"swedensouth", "Sweden South", PublicCloud},
+	{"centraluseuap", "Central US EUAP", PublicCloud},
+	{"eastus2euap", "East US 2 EUAP", PublicCloud},
+	{"eastusslv", "East US SLV", PublicCloud},
+
+	// US Government cloud
+	{"usgovvirginia", "US Gov Virginia", USGovernmentCloud},
+	{"usgovarizona", "US Gov Arizona", USGovernmentCloud},
+	{"usgovtexas", "US Gov Texas", USGovernmentCloud},
+	{"usgoviowa", "US Gov Iowa", USGovernmentCloud},
+	{"usdodcentral", "US DoD Central", USGovernmentCloud},
+	{"usdodeast", "US DoD East", USGovernmentCloud},
+
+	// China cloud
+	{"chinaeast", "China East", ChinaCloud},
+	{"chinaeast2", "China East 2", ChinaCloud},
+	{"chinaeast3", "China East 3", ChinaCloud},
+	{"chinanorth", "China North", ChinaCloud},
+	{"chinanorth2", "China North 2", ChinaCloud},
+	{"chinanorth3", "China North 3", ChinaCloud},
+}
@@ -1,60 +1,12 @@
 package azurerm
 
-var (
-	locationDisplayToName = map[string]string{
-		"West US":              "westus",
-		"West US 2":            "westus2",
-		"East US":              "eastus",
-		"Central US":           "centralus",
-		"Central US EUAP":      "centraluseuap",
-		"South Central US":     "southcentralus",
-		"North Central US":     "northcentralus",
-		"West Central US":      "westcentralus",
-		"East US 2":            "eastus2",
-		"East US 2 EUAP":       "eastus2euap",
-		"Brazil South":         "brazilsouth",
-		"Brazil US":            "brazilus",
-		"North Europe":         "northeurope",
-		"West Europe":          "westeurope",
-		"East Asia":            "eastasia",
-		"Southeast Asia":       "southeastasia",
-		"Japan West":           "japanwest",
-		"Japan East":           "japaneast",
-		"Korea Central":        "koreacentral",
-		"Korea South":          "koreasouth",
-		"South India":          "southindia",
-		"West India":           "westindia",
-		"Central India":        "centralindia",
-		"Australia East":       "australiaeast",
-		"Australia Southeast":  "australiasoutheast",
-		"Canada Central":       "canadacentral",
-		"Canada East":          "canadaeast",
-		"UK South":             "uksouth",
-		"UK West":              "ukwest",
-		"France Central":       "francecentral",
-		"France South":         "francesouth",
-		"Australia Central":    "australiacentral",
-		"Australia Central 2":  "australiacentral2",
-		"UAE Central":          "uaecentral",
-		"UAE North":            "uaenorth",
-		"South Africa North":   "southafricanorth",
-		"South Africa West":    "southafricawest",
-		"Switzerland North":    "switzerlandnorth",
-		"Switzerland West":     "switzerlandwest",
-		"Germany North":        "germanynorth",
-		"Germany West Central": "germanywestcentral",
-		"Norway East":          "norwayeast",
-		"Norway West":          "norwaywest",
-		"Brazil Southeast":     "brazilsoutheast",
-		"West US 3":            "westus3",
-		"East US SLV":          "eastusslv",
-		"Sweden Central":       "swedencentral",
-		"Sweden South":         "swedensouth",
-	}
-)
+import "github.com/kaytu-io/pennywise/pkg/parser/azurerm/location"
 
-func GetRegionCode(location string) string {
-	return locationDisplayToName[location]
+// GetRegionCode returns the name of the location used by the APIs, ex: westeurope for West Europe, westeurope
+// or west-europe. It returns an empty string if the location is not known, see location.Normalize for the error.
+func GetRegionCode(loc string) string {
+	name, _ := location.Normalize(loc)
+	return name
 }
 
 // Provider is an implementation of the terraform.Provider, used to extract component queries from
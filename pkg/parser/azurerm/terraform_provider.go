@@ -6,11 +6,15 @@ import (
 
 // RegistryName is the fully qualified name under which this provider is stored in the registry.
 const RegistryName = "registry.terraform.io/hashicorp/azurerm"
+
+// OpenTofuRegistryName is the fully qualified name under which this provider is stored in the OpenTofu registry.
+const OpenTofuRegistryName = "registry.opentofu.org/hashicorp/azurerm"
+
 const ProviderName = "azurerm"
 
-// TerraformProviderInitializer is a terraform.ProviderInitializer that initializes the default GCP provider.
+// TerraformProviderInitializer is a terraform.ProviderInitializer that initializes the default Azure provider.
 var TerraformProviderInitializer = terraform.ProviderInitializer{
-	MatchNames: []string{ProviderName, RegistryName},
+	MatchNames: []string{ProviderName, RegistryName, OpenTofuRegistryName},
 	Provider: func(values map[string]interface{}) (terraform.Provider, error) {
 		return NewProvider(ProviderName)
 	},
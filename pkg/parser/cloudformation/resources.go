@@ -0,0 +1,302 @@
+package cloudformation
+
+import (
+	"strings"
+
+	"github.com/kaytu-io/pennywise/pkg/parser/properties"
+)
+
+// resourceMapper returns the terraform resource type equivalent to a CloudFormation resource and its
+// properties as the terraform attributes, an empty type means the resource can not be mapped
+type resourceMapper func(props properties.Properties) (string, map[string]interface{})
+
+// resourceMappers are the resourceMapper of each supported CloudFormation resource type
+var resourceMappers = map[string]resourceMapper{
+	"AWS::EC2::Instance":                               ec2Instance,
+	"AWS::EC2::Volume":                                 ec2Volume,
+	"AWS::EC2::EIP":                                    ec2EIP,
+	"AWS::EC2::Host":                                   ec2Host,
+	"AWS::EC2::NatGateway":                             ec2NatGateway,
+	"AWS::RDS::DBInstance":                             rdsDBInstance,
+	"AWS::EFS::FileSystem":                             efsFileSystem,
+	"AWS::EKS::Cluster":                                eksCluster,
+	"AWS::EKS::Nodegroup":                              eksNodegroup,
+	"AWS::ElastiCache::CacheCluster":                   elastiCacheCluster,
+	"AWS::ElastiCache::ReplicationGroup":               elastiCacheReplicationGroup,
+	"AWS::Elasticsearch::Domain":                       elasticsearchDomain,
+	"AWS::OpenSearchService::Domain":                   openSearchDomain,
+	"AWS::Lambda::Function":                            lambdaFunction,
+	"AWS::ElasticLoadBalancingV2::LoadBalancer":        loadBalancerV2,
+	"AWS::ElasticLoadBalancing::LoadBalancer":          classicLoadBalancer,
+	"AWS::ECR::Repository":                             ecrRepository,
+	"AWS::FSx::FileSystem":                             fsxFileSystem,
+	"AWS::AutoScaling::AutoScalingGroup":               autoScalingGroup,
+	"AWS::ElasticLoadBalancingV2::TargetGroup":         freeResource("aws_lb_target_group"),
+	"AWS::ElasticLoadBalancingV2::Listener":            freeResource("aws_lb_listener"),
+	"AWS::EC2::SecurityGroup":                          freeResource("aws_security_group"),
+	"AWS::EC2::VPC":                                    freeResource("aws_vpc"),
+	"AWS::EC2::Subnet":                                 freeResource("aws_subnet"),
+	"AWS::EC2::RouteTable":                             freeResource("aws_route_table"),
+	"AWS::EC2::InternetGateway":                        freeResource("aws_internet_gateway"),
+	"AWS::IAM::Role":                                   freeResource("aws_iam_role"),
+	"AWS::IAM::Policy":                                 freeResource("aws_iam_policy"),
+	"AWS::IAM::InstanceProfile":                        freeResource("aws_iam_instance_profile"),
+	"AWS::EC2::LaunchTemplate":                         freeResource("aws_launch_template"),
+	"AWS::AutoScaling::LaunchConfiguration":            freeResource("aws_launch_configuration"),
+	"AWS::RDS::DBSubnetGroup":                          freeResource("aws_db_subnet_group"),
+	"AWS::ElastiCache::SubnetGroup":                    freeResource("aws_elasticache_subnet_group"),
+	"AWS::EC2::SubnetRouteTableAssociation":            freeResource("aws_route_table_association"),
+	"AWS::EC2::VPCGatewayAttachment":                   freeResource("aws_internet_gateway_attachment"),
+	"AWS::ElasticLoadBalancingV2::ListenerRule":        freeResource("aws_lb_listener_rule"),
+	"AWS::EC2::SecurityGroupIngress":                   freeResource("aws_security_group_rule"),
+	"AWS::EC2::SecurityGroupEgress":                    freeResource("aws_security_group_rule"),
+	"AWS::EC2::Route":                                  freeResource("aws_route"),
+	"AWS::RDS::DBParameterGroup":                       freeResource("aws_db_parameter_group"),
+	"AWS::ElastiCache::ParameterGroup":                 freeResource("aws_elasticache_parameter_group"),
+	"AWS::Lambda::Permission":                          freeResource("aws_lambda_permission"),
+	"AWS::EFS::MountTarget":                            freeResource("aws_efs_mount_target"),
+	"AWS::EC2::EIPAssociation":                         freeResource("aws_eip_association"),
+	"AWS::ElasticLoadBalancingV2::ListenerCertificate": freeResource("aws_lb_listener_certificate"),
+}
+
+func ec2Instance(props properties.Properties) (string, map[string]interface{}) {
+	values := map[string]interface{}{
+		// InstanceType defaults to m1.small on CloudFormation
+		"instance_type": props.StringOr("m1.small", "InstanceType"),
+	}
+	properties.SetValue(values, "ami", props.Value("ImageId"))
+	properties.SetValue(values, "tenancy", props.Value("Tenancy"))
+	properties.SetValue(values, "availability_zone", props.Value("AvailabilityZone"))
+	properties.SetValue(values, "ebs_optimized", props.Boolean("EbsOptimized"))
+	properties.SetValue(values, "monitoring", props.Boolean("Monitoring"))
+	if credits := props.Value("CreditSpecification", "CPUCredits"); credits != nil {
+		values["credit_specification"] = []interface{}{map[string]interface{}{"cpu_credits": credits}}
+	}
+	var blockDevices []interface{}
+	for _, bd := range props.List("BlockDeviceMappings") {
+		ebs := bd.Object("Ebs")
+		if ebs == nil {
+			continue
+		}
+		device := map[string]interface{}{}
+		properties.SetValue(device, "device_name", bd.Value("DeviceName"))
+		properties.SetValue(device, "volume_size", ebs.Number("VolumeSize"))
+		properties.SetValue(device, "volume_type", ebs.Value("VolumeType"))
+		properties.SetValue(device, "iops", ebs.Number("Iops"))
+		properties.SetValue(device, "throughput", ebs.Number("Throughput"))
+		blockDevices = append(blockDevices, device)
+	}
+	if len(blockDevices) > 0 {
+		values["ebs_block_device"] = blockDevices
+	}
+	return "aws_instance", values
+}
+
+func ec2Volume(props properties.Properties) (string, map[string]interface{}) {
+	values := map[string]interface{}{
+		"type": props.StringOr("gp2", "VolumeType"),
+	}
+	properties.SetValue(values, "availability_zone", props.Value("AvailabilityZone"))
+	properties.SetValue(values, "size", props.Number("Size"))
+	properties.SetValue(values, "iops", props.Number("Iops"))
+	properties.SetValue(values, "throughput", props.Number("Throughput"))
+	return "aws_ebs_volume", values
+}
+
+func ec2EIP(props properties.Properties) (string, map[string]interface{}) {
+	values := map[string]interface{}{}
+	properties.SetValue(values, "domain", props.Value("Domain"))
+	properties.SetValue(values, "instance", props.Value("InstanceId"))
+	return "aws_eip", values
+}
+
+func ec2Host(props properties.Properties) (string, map[string]interface{}) {
+	values := map[string]interface{}{}
+	properties.SetValue(values, "instance_type", props.Value("InstanceType"))
+	properties.SetValue(values, "instance_family", props.Value("InstanceFamily"))
+	properties.SetValue(values, "availability_zone", props.Value("AvailabilityZone"))
+	return "aws_ec2_host", values
+}
+
+func ec2NatGateway(props properties.Properties) (string, map[string]interface{}) {
+	return "aws_nat_gateway", map[string]interface{}{
+		"connectivity_type": props.StringOr("public", "ConnectivityType"),
+	}
+}
+
+func rdsDBInstance(props properties.Properties) (string, map[string]interface{}) {
+	values := map[string]interface{}{}
+	properties.SetValue(values, "instance_class", props.Value("DBInstanceClass"))
+	properties.SetValue(values, "engine", props.Value("Engine"))
+	properties.SetValue(values, "engine_version", props.Value("EngineVersion"))
+	properties.SetValue(values, "allocated_storage", props.Number("AllocatedStorage"))
+	properties.SetValue(values, "storage_type", props.Value("StorageType"))
+	properties.SetValue(values, "iops", props.Number("Iops"))
+	properties.SetValue(values, "multi_az", props.Boolean("MultiAZ"))
+	properties.SetValue(values, "license_model", props.Value("LicenseModel"))
+	properties.SetValue(values, "backup_retention_period", props.Number("BackupRetentionPeriod"))
+	return "aws_db_instance", values
+}
+
+func efsFileSystem(props properties.Properties) (string, map[string]interface{}) {
+	values := map[string]interface{}{
+		"throughput_mode": props.StringOr("bursting", "ThroughputMode"),
+	}
+	properties.SetValue(values, "performance_mode", props.Value("PerformanceMode"))
+	properties.SetValue(values, "provisioned_throughput_in_mibps", props.Number("ProvisionedThroughputInMibps"))
+	properties.SetValue(values, "availability_zone_name", props.Value("AvailabilityZoneName"))
+	return "aws_efs_file_system", values
+}
+
+func eksCluster(props properties.Properties) (string, map[string]interface{}) {
+	values := map[string]interface{}{}
+	properties.SetValue(values, "name", props.Value("Name"))
+	properties.SetValue(values, "version", props.Value("Version"))
+	return "aws_eks_cluster", values
+}
+
+func eksNodegroup(props properties.Properties) (string, map[string]interface{}) {
+	values := map[string]interface{}{
+		"capacity_type": props.StringOr("ON_DEMAND", "CapacityType"),
+	}
+	properties.SetValue(values, "instance_types", props.Value("InstanceTypes"))
+	properties.SetValue(values, "disk_size", props.Number("DiskSize"))
+	properties.SetValue(values, "ami_type", props.Value("AmiType"))
+	if scaling := props.Object("ScalingConfig"); scaling != nil {
+		config := map[string]interface{}{}
+		properties.SetValue(config, "desired_size", scaling.Number("DesiredSize"))
+		properties.SetValue(config, "min_size", scaling.Number("MinSize"))
+		properties.SetValue(config, "max_size", scaling.Number("MaxSize"))
+		values["scaling_config"] = []interface{}{config}
+	}
+	return "aws_eks_node_group", values
+}
+
+func elastiCacheCluster(props properties.Properties) (string, map[string]interface{}) {
+	values := map[string]interface{}{}
+	properties.SetValue(values, "node_type", props.Value("CacheNodeType"))
+	properties.SetValue(values, "engine", props.Value("Engine"))
+	properties.SetValue(values, "num_cache_nodes", props.Number("NumCacheNodes"))
+	properties.SetValue(values, "snapshot_retention_limit", props.Number("SnapshotRetentionLimit"))
+	return "aws_elasticache_cluster", values
+}
+
+func elastiCacheReplicationGroup(props properties.Properties) (string, map[string]interface{}) {
+	values := map[string]interface{}{}
+	properties.SetValue(values, "node_type", props.Value("CacheNodeType"))
+	properties.SetValue(values, "engine", props.Value("Engine"))
+	properties.SetValue(values, "num_cache_clusters", props.Number("NumCacheClusters"))
+	properties.SetValue(values, "num_node_groups", props.Number("NumNodeGroups"))
+	properties.SetValue(values, "replicas_per_node_group", props.Number("ReplicasPerNodeGroup"))
+	properties.SetValue(values, "snapshot_retention_limit", props.Number("SnapshotRetentionLimit"))
+	return "aws_elasticache_replication_group", values
+}
+
+func elasticsearchDomain(props properties.Properties) (string, map[string]interface{}) {
+	return "aws_elasticsearch_domain", searchDomain(props.Object("ElasticsearchClusterConfig"), props.Object("EBSOptions"))
+}
+
+func openSearchDomain(props properties.Properties) (string, map[string]interface{}) {
+	return "aws_opensearch_domain", searchDomain(props.Object("ClusterConfig"), props.Object("EBSOptions"))
+}
+
+// searchDomain returns the values of an Elasticsearch or OpenSearch domain, both have the same attributes
+func searchDomain(cluster, ebs properties.Properties) map[string]interface{} {
+	values := map[string]interface{}{}
+	if cluster != nil {
+		config := map[string]interface{}{}
+		properties.SetValue(config, "instance_type", cluster.Value("InstanceType"))
+		properties.SetValue(config, "instance_count", cluster.Number("InstanceCount"))
+		properties.SetValue(config, "dedicated_master_enabled", cluster.Boolean("DedicatedMasterEnabled"))
+		properties.SetValue(config, "dedicated_master_type", cluster.Value("DedicatedMasterType"))
+		properties.SetValue(config, "dedicated_master_count", cluster.Number("DedicatedMasterCount"))
+		properties.SetValue(config, "warm_enabled", cluster.Boolean("WarmEnabled"))
+		properties.SetValue(config, "warm_type", cluster.Value("WarmType"))
+		properties.SetValue(config, "warm_count", cluster.Number("WarmCount"))
+		values["cluster_config"] = []interface{}{config}
+	}
+	if ebs != nil {
+		options := map[string]interface{}{}
+		properties.SetValue(options, "ebs_enabled", ebs.Boolean("EBSEnabled"))
+		properties.SetValue(options, "volume_size", ebs.Number("VolumeSize"))
+		properties.SetValue(options, "volume_type", ebs.Value("VolumeType"))
+		properties.SetValue(options, "iops", ebs.Number("Iops"))
+		properties.SetValue(options, "throughput", ebs.Number("Throughput"))
+		values["ebs_options"] = []interface{}{options}
+	}
+	return values
+}
+
+func lambdaFunction(props properties.Properties) (string, map[string]interface{}) {
+	values := map[string]interface{}{
+		"memory_size": props.NumberOr(128, "MemorySize"),
+	}
+	properties.SetValue(values, "runtime", props.Value("Runtime"))
+	properties.SetValue(values, "architectures", props.Value("Architectures"))
+	if size := props.Number("EphemeralStorage", "Size"); size != nil {
+		values["ephemeral_storage"] = []interface{}{map[string]interface{}{"size": size}}
+	}
+	return "aws_lambda_function", values
+}
+
+func loadBalancerV2(props properties.Properties) (string, map[string]interface{}) {
+	return "aws_lb", map[string]interface{}{
+		"load_balancer_type": props.StringOr("application", "Type"),
+		"internal":           props.StringOr("internet-facing", "Scheme") == "internal",
+	}
+}
+
+func classicLoadBalancer(props properties.Properties) (string, map[string]interface{}) {
+	return "aws_elb", map[string]interface{}{
+		"internal": props.StringOr("internet-facing", "Scheme") == "internal",
+	}
+}
+
+func ecrRepository(props properties.Properties) (string, map[string]interface{}) {
+	values := map[string]interface{}{}
+	properties.SetValue(values, "name", props.Value("RepositoryName"))
+	return "aws_ecr_repository", values
+}
+
+func fsxFileSystem(props properties.Properties) (string, map[string]interface{}) {
+	var rType string
+	var config properties.Properties
+	switch strings.ToUpper(props.StringOr("", "FileSystemType")) {
+	case "LUSTRE":
+		rType, config = "aws_fsx_lustre_file_system", props.Object("LustreConfiguration")
+	case "WINDOWS":
+		rType, config = "aws_fsx_windows_file_system", props.Object("WindowsConfiguration")
+	case "ONTAP":
+		rType, config = "aws_fsx_ontap_file_system", props.Object("OntapConfiguration")
+	case "OPENZFS":
+		rType, config = "aws_fsx_openzfs_file_system", props.Object("OpenZFSConfiguration")
+	default:
+		return "", nil
+	}
+	values := map[string]interface{}{}
+	properties.SetValue(values, "storage_capacity", props.Number("StorageCapacity"))
+	properties.SetValue(values, "storage_type", props.Value("StorageType"))
+	if config != nil {
+		properties.SetValue(values, "deployment_type", config.Value("DeploymentType"))
+		properties.SetValue(values, "throughput_capacity", config.Number("ThroughputCapacity"))
+		properties.SetValue(values, "per_unit_storage_throughput", config.Number("PerUnitStorageThroughput"))
+		properties.SetValue(values, "automatic_backup_retention_days", config.Number("AutomaticBackupRetentionDays"))
+	}
+	return rType, values
+}
+
+func autoScalingGroup(props properties.Properties) (string, map[string]interface{}) {
+	values := map[string]interface{}{}
+	properties.SetValue(values, "min_size", props.Number("MinSize"))
+	properties.SetValue(values, "max_size", props.Number("MaxSize"))
+	properties.SetValue(values, "desired_capacity", props.Number("DesiredCapacity"))
+	return "aws_autoscaling_group", values
+}
+
+// freeResource returns a resourceMapper for resource types that have no cost
+// so they are listed along with the other resources
+func freeResource(rType string) resourceMapper {
+	return func(props properties.Properties) (string, map[string]interface{}) {
+		return rType, map[string]interface{}{}
+	}
+}
@@ -0,0 +1,224 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/awslabs/goformation/v4/intrinsics"
+	"github.com/kaytu-io/pennywise/pkg/parser/properties"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	"github.com/kaytu-io/pennywise/pkg/usage"
+)
+
+// Template is a CloudFormation template with all the parameters, conditions
+// and intrinsic functions already evaluated
+type Template struct {
+	Conditions map[string]interface{} `json:"Conditions"`
+	Resources  map[string]Resource    `json:"Resources"`
+}
+
+// Resource is a resource of a CloudFormation template
+type Resource struct {
+	Type       string                 `json:"Type"`
+	Condition  string                 `json:"Condition"`
+	Properties map[string]interface{} `json:"Properties"`
+}
+
+// Options are the options used to evaluate a template
+type Options struct {
+	// Parameters overrides the default values of the template parameters
+	Parameters map[string]interface{}
+	// Region is the region the stack is deployed to, it's the value of AWS::Region
+	Region string
+	// StackName is the value of AWS::StackName
+	StackName string
+}
+
+// ParseTemplate reads the CloudFormation template (JSON or YAML) on the path and evaluates it with the options
+func ParseTemplate(path string, opts Options) (*Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", path, err)
+	}
+
+	// The template is first converted to JSON without processing the intrinsic
+	// functions so the resources conditions can be taken out, otherwise they are
+	// replaced by their value when the conditions are evaluated
+	raw, err := intrinsics.ProcessYAML(content, &intrinsics.ProcessorOptions{NoProcess: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+	var rawTemplate map[string]interface{}
+	if err := json.Unmarshal(raw, &rawTemplate); err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+	resourceConditions := make(map[string]string)
+	if resources, ok := rawTemplate["Resources"].(map[string]interface{}); ok {
+		for name, r := range resources {
+			res, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if condition, ok := res["Condition"].(string); ok {
+				resourceConditions[name] = condition
+				delete(res, "Condition")
+			}
+		}
+	}
+	raw, err = json.Marshal(rawTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	processed, err := intrinsics.ProcessJSON(raw, &intrinsics.ProcessorOptions{
+		ParameterOverrides: opts.Parameters,
+		EvaluateConditions: true,
+		IntrinsicHandlerOverrides: map[string]intrinsics.IntrinsicHandler{
+			"Ref":        opts.ref,
+			"Fn::GetAZs": opts.getAZs,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate template %s: %w", path, err)
+	}
+
+	var template Template
+	if err := json.Unmarshal(processed, &template); err != nil {
+		return nil, fmt.Errorf("failed to evaluate template %s: %w", path, err)
+	}
+	for name, condition := range resourceConditions {
+		res := template.Resources[name]
+		res.Condition = condition
+		template.Resources[name] = res
+	}
+	return &template, nil
+}
+
+// ref resolves the Ref intrinsic function, the pseudo parameters are resolved with the options
+// and the references to resources with their logical ID
+func (opts Options) ref(name string, input interface{}, template interface{}) interface{} {
+	ref, ok := input.(string)
+	if !ok {
+		return nil
+	}
+	switch ref {
+	case "AWS::Region":
+		if opts.Region != "" {
+			return opts.Region
+		}
+	case "AWS::StackName":
+		if opts.StackName != "" {
+			return opts.StackName
+		}
+	}
+	if t, ok := template.(map[string]interface{}); ok {
+		if resources, ok := t["Resources"].(map[string]interface{}); ok {
+			if _, ok := resources[ref]; ok {
+				return ref
+			}
+		}
+	}
+	return intrinsics.Ref(name, input, template)
+}
+
+// getAZs resolves the Fn::GetAZs intrinsic function with the first availability zones of the region
+func (opts Options) getAZs(name string, input interface{}, template interface{}) interface{} {
+	region, ok := input.(string)
+	if !ok || region == "" {
+		region = opts.Region
+	}
+	if region == "" {
+		return intrinsics.FnGetAZs(name, input, template)
+	}
+	return []interface{}{region + "a", region + "b", region + "c"}
+}
+
+// GetResources returns the resources of the template that are created and have an equivalent terraform
+// resource type, the other resource types are returned as unsupported
+func (t *Template) GetResources(region string, u usage.Usage) ([]schema.ResourceDef, []string) {
+	var names []string
+	for name := range t.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var resources []schema.ResourceDef
+	var unsupported []string
+	for _, name := range names {
+		res := t.Resources[name]
+		if res.Condition != "" && !t.conditionValue(res.Condition) {
+			continue
+		}
+		mapper, ok := resourceMappers[res.Type]
+		if !ok {
+			unsupported = append(unsupported, res.Type)
+			continue
+		}
+		rType, values := mapper(properties.Properties(res.Properties))
+		if rType == "" {
+			unsupported = append(unsupported, res.Type)
+			continue
+		}
+		address := fmt.Sprintf("%s.%s", rType, name)
+		values[usage.Key] = u.GetUsage(rType, address)
+		resources = append(resources, schema.ResourceDef{
+			Address:      address,
+			Type:         rType,
+			Name:         name,
+			RegionCode:   region,
+			ProviderName: schema.AWSProvider,
+			Values:       values,
+		})
+	}
+	return resources, unsupported
+}
+
+// conditionValue returns the evaluated value of the condition, unknown conditions are considered true
+// so the resources depending on them are still estimated
+func (t *Template) conditionValue(name string) bool {
+	value, ok := t.Conditions[name].(bool)
+	if !ok {
+		return true
+	}
+	return value
+}
+
+// ReadParameters reads a parameters file, it supports the format used by the AWS CLI
+// ([{"ParameterKey": "Key", "ParameterValue": "Value"}]), the one used by CodePipeline
+// ({"Parameters": {"Key": "Value"}}) and a plain JSON or YAML object of keys and values.
+func ReadParameters(path string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parameters file %s: %w", path, err)
+	}
+	if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+		content, err = intrinsics.ProcessYAML(content, &intrinsics.ProcessorOptions{NoProcess: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse parameters file %s: %w", path, err)
+		}
+	}
+
+	var cliParameters []struct {
+		ParameterKey   string      `json:"ParameterKey"`
+		ParameterValue interface{} `json:"ParameterValue"`
+	}
+	if err := json.Unmarshal(content, &cliParameters); err == nil {
+		parameters := make(map[string]interface{})
+		for _, p := range cliParameters {
+			parameters[p.ParameterKey] = p.ParameterValue
+		}
+		return parameters, nil
+	}
+
+	var parameters map[string]interface{}
+	if err := json.Unmarshal(content, &parameters); err != nil {
+		return nil, fmt.Errorf("failed to parse parameters file %s: %w", path, err)
+	}
+	if p, ok := parameters["Parameters"].(map[string]interface{}); ok {
+		return p, nil
+	}
+	return parameters, nil
+}
@@ -0,0 +1,19 @@
+package google
+
+// Provider is an implementation of the terraform.Provider, used to extract component queries from
+// terraform resources.
+type Provider struct {
+	key    string
+	region string
+}
+
+// NewProvider returns a new Provider with the provided default region and a query key.
+func NewProvider(key string, region string) (*Provider, error) {
+	return &Provider{key: key, region: region}, nil
+}
+
+// Name returns the Provider's common name.
+func (p *Provider) Name() string { return p.key }
+
+// Region returns the default region of the resources of the Provider.
+func (p *Provider) Region() string { return p.region }
@@ -0,0 +1,53 @@
+package region
+
+import (
+	"regexp"
+	"strings"
+)
+
+// zoneRegex matches the zones, a region followed by the zone letter, ex: us-central1-a
+var zoneRegex = regexp.MustCompile(`^([a-z]+-[a-z]+[0-9]+)-[a-z]$`)
+
+// regionRegex matches the regions, ex: us-central1 or europe-west4
+var regionRegex = regexp.MustCompile(`^[a-z]+-[a-z]+[0-9]+$`)
+
+// IsZone returns true if the location is a zone, ex: us-central1-a
+func IsZone(location string) bool {
+	return zoneRegex.MatchString(location)
+}
+
+// FromZone returns the region of the zone, ex: us-central1 for us-central1-a. The location
+// is returned as is if it's not a zone.
+func FromZone(zone string) string {
+	if m := zoneRegex.FindStringSubmatch(zone); m != nil {
+		return m[1]
+	}
+	return zone
+}
+
+// FromValues returns the region of a resource from its region, zone or location attributes, the location
+// can be a zone, a region or a multi-region (ex: US for the storage buckets) which is returned lower cased.
+// The defaultRegion (the region of the provider) is returned if the resource has none of them.
+func FromValues(values map[string]interface{}, defaultRegion string) string {
+	if r, ok := values["region"].(string); ok && regionRegex.MatchString(r) {
+		return r
+	}
+	if z, ok := values["zone"].(string); ok && IsZone(z) {
+		return FromZone(z)
+	}
+	if l, ok := values["location"].(string); ok && l != "" && !strings.Contains(l, ".") {
+		return FromZone(strings.ToLower(l))
+	}
+	return defaultRegion
+}
+
+// FromConfig returns the region of a provider configuration, the region of its zone is used if it has no region
+func FromConfig(region, zone string) string {
+	if region != "" {
+		return region
+	}
+	if IsZone(zone) {
+		return FromZone(zone)
+	}
+	return ""
+}
@@ -0,0 +1,40 @@
+package google
+
+import (
+	"github.com/kaytu-io/pennywise/pkg/parser/google/region"
+	"github.com/kaytu-io/pennywise/pkg/parser/terraform"
+)
+
+const (
+	// RegistryName is the fully qualified name under which this provider is stored in the registry.
+	RegistryName = "registry.terraform.io/hashicorp/google"
+	// BetaRegistryName is the fully qualified name of the beta provider, it has the same resources.
+	BetaRegistryName = "registry.terraform.io/hashicorp/google-beta"
+
+	// OpenTofuRegistryName is the fully qualified name under which this provider is stored in the OpenTofu registry.
+	OpenTofuRegistryName = "registry.opentofu.org/hashicorp/google"
+	// OpenTofuBetaRegistryName is the fully qualified name of the beta provider in the OpenTofu registry.
+	OpenTofuBetaRegistryName = "registry.opentofu.org/hashicorp/google-beta"
+
+	// DefaultRegion is the region used by default when none is defined on the provider
+	DefaultRegion = "us-central1"
+
+	ProviderName     = "google"
+	BetaProviderName = "google-beta"
+)
+
+// TerraformProviderInitializer is a terraform.ProviderInitializer that initializes the default Google provider.
+var TerraformProviderInitializer = terraform.ProviderInitializer{
+	MatchNames: []string{ProviderName, BetaProviderName, RegistryName, BetaRegistryName, OpenTofuRegistryName, OpenTofuBetaRegistryName},
+	Provider: func(values map[string]interface{}) (terraform.Provider, error) {
+		r, _ := values["region"].(string)
+		z, _ := values["zone"].(string)
+		// If neither the region nor the zone is defined they are passed via ENV variables
+		// or the gcloud config so we'll assume the region to be the DefaultRegion
+		reg := region.FromConfig(r, z)
+		if reg == "" {
+			reg = DefaultRegion
+		}
+		return NewProvider(ProviderName, reg)
+	},
+}
@@ -0,0 +1,104 @@
+package hcl
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	usagePackage "github.com/kaytu-io/pennywise/pkg/usage"
+)
+
+const (
+	// CDKTFOutDir is the directory where cdktf synth writes the synthesized stacks
+	CDKTFOutDir = "cdktf.out"
+	// cdktfStackFile is the terraform configuration of a synthesized stack
+	cdktfStackFile = "cdk.tf.json"
+)
+
+// CDKTFStack is a stack synthesized by cdktf synth
+type CDKTFStack struct {
+	Name string
+	// Dir is the directory of the stack terraform configuration, ex: cdktf.out/stacks/<name>
+	Dir string
+}
+
+// cdktfManifest is the manifest.json written by cdktf synth
+type cdktfManifest struct {
+	Stacks map[string]struct {
+		Name                 string `json:"name"`
+		SynthesizedStackPath string `json:"synthesizedStackPath"`
+	} `json:"stacks"`
+}
+
+// GetCDKTFStacks returns the synthesized stacks of the CDKTF project on the path, the path can be the
+// project directory or its cdktf.out directory. It returns no stacks if the path is not a CDKTF project.
+func GetCDKTFStacks(path string) ([]CDKTFStack, error) {
+	outDir := filepath.Join(path, CDKTFOutDir)
+	if filepath.Base(filepath.Clean(path)) == CDKTFOutDir {
+		outDir = path
+	}
+
+	var stacks []CDKTFStack
+	if content, err := os.ReadFile(filepath.Join(outDir, "manifest.json")); err == nil {
+		var manifest cdktfManifest
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse the cdktf manifest: %w", err)
+		}
+		for key, s := range manifest.Stacks {
+			name := s.Name
+			if name == "" {
+				name = key
+			}
+			stacks = append(stacks, CDKTFStack{
+				Name: name,
+				Dir:  filepath.Join(outDir, filepath.Dir(s.SynthesizedStackPath)),
+			})
+		}
+	} else {
+		files, err := filepath.Glob(filepath.Join(outDir, "stacks", "*", cdktfStackFile))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			dir := filepath.Dir(f)
+			stacks = append(stacks, CDKTFStack{Name: filepath.Base(dir), Dir: dir})
+		}
+	}
+	sort.Slice(stacks, func(i, j int) bool {
+		return stacks[i].Name < stacks[j].Name
+	})
+	return stacks, nil
+}
+
+// ParseCDKTFProject parses every stack synthesized by cdktf as a separate project, each stack is returned as
+// a child module with the stack name as its address. The var files of the opts are relative to the path.
+func ParseCDKTFProject(stacks []CDKTFStack, path string, usage usagePackage.Usage, opts Options) (*schema.ModuleDef, error) {
+	currentDir, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	// Stacks are evaluated on their own directory so the var files need to be absolute
+	stackOpts := opts
+	stackOpts.VarFiles = nil
+	for _, f := range opts.VarFiles {
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(currentDir, f)
+		}
+		stackOpts.VarFiles = append(stackOpts.VarFiles, f)
+	}
+
+	var projectsModule schema.ModuleDef
+	for _, stack := range stacks {
+		stackModule, err := ParseHclResources(stack.Dir, usage, stackOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse stack %s: %w", stack.Name, err)
+		}
+		changeResourcesId(stack.Name, stackModule)
+		stackModule.Address = stack.Name
+		projectsModule.ChildModules = append(projectsModule.ChildModules, *stackModule)
+	}
+	return &projectsModule, nil
+}
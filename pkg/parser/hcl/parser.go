@@ -10,7 +10,18 @@ import (
 	"golang.org/x/net/context"
 )
 
-func ParseHclResources(path string, usage usagePackage.Usage, tfVarFiles []string) (*schema.ModuleDef, error) {
+// Options are the options used to evaluate a terraform or terragrunt project
+type Options struct {
+	// VarFiles are the variables files passed to the project, relative to the project path
+	VarFiles []string
+	// Env are the environment variables (ex: TF_VAR_x) used to evaluate the project
+	Env map[string]string
+	// Workspace is the terraform workspace the project is evaluated for, default if empty
+	Workspace string
+}
+
+// ParseHclResources parses the terraform project on the path
+func ParseHclResources(path string, usage usagePackage.Usage, opts Options) (*schema.ModuleDef, error) {
 	var rootModule Module
 	runCtx, err := config.NewRunContextFromEnv(context.Background())
 	if err != nil {
@@ -18,8 +29,10 @@ func ParseHclResources(path string, usage usagePackage.Usage, tfVarFiles []strin
 	}
 	ctx := config.ProjectContext{
 		ProjectConfig: &config.Project{
-			Path:              path,
-			TerraformVarFiles: tfVarFiles,
+			Path:               path,
+			TerraformVarFiles:  opts.VarFiles,
+			TerraformWorkspace: opts.Workspace,
+			Env:                opts.Env,
 		},
 		RunContext: runCtx,
 	}
@@ -30,44 +43,25 @@ func ParseHclResources(path string, usage usagePackage.Usage, tfVarFiles []strin
 	if providerErr != nil {
 		return nil, providerErr
 	}
-	var provider schema.ProviderName
-	var defaultRegion string
+	var project Project
 	jsons := h.LoadPlanJSONs()
 	if len(jsons) > 1 {
 		return nil, fmt.Errorf("multiple projects found, please provide one project")
 	}
 	for _, j := range jsons {
-		var res Project
-		err := json.Unmarshal(j.JSON, &res)
+		err := json.Unmarshal(j.JSON, &project)
 		if err != nil {
 			return nil, err
 		}
-		for key, providerConfig := range res.Configuration.ProviderConfig {
-			if _, ok := map[string]bool{
-				"aws":     true,
-				"azure":   true,
-				"azurerm": true,
-			}[string(key)]; ok {
-				provider = key
-				defaultRegion = providerConfig.Expressions.Region.ConstantValue
-				break
-			}
-		}
-		for _, mod := range res.PlannedValues {
+		for _, mod := range project.PlannedValues {
 			rootModule = mod
 		}
 	}
 
 	addUsageToModule(usage, &rootModule)
 
-	parsedProjects := &ParsedProject{
-		Directory:     path,
-		Provider:      provider,
-		DefaultRegion: defaultRegion,
-		RootModule:    rootModule,
-	}
-
-	projectModule := parsedProjects.GetModule()
+	parsedProject := newParsedProject(path, project, rootModule)
+	projectModule := parsedProject.GetModule()
 
 	return &projectModule, nil
 }
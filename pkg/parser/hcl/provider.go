@@ -0,0 +1,71 @@
+package hcl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kaytu-io/pennywise/pkg/parser/registry"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+)
+
+// indexRegex matches the count and for_each indexes of the addresses, ex: [0] or ["a"]
+var indexRegex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// ProviderRegion is the provider of a provider configuration and the default region of its resources
+type ProviderRegion struct {
+	Provider      schema.ProviderName
+	DefaultRegion string
+}
+
+// projectProviders returns the providers of the project configurations by their keys (ex: aws or aws.west),
+// the configurations of the providers that are not registered are ignored
+func projectProviders(project Project) map[string]ProviderRegion {
+	providers := make(map[string]ProviderRegion)
+	for key, providerConfig := range project.Configuration.ProviderConfig {
+		if p, ok := registry.Lookup(key); ok {
+			region := providerConfig.Expressions.Region.ConstantValue
+			if region == "" {
+				region = p.DefaultRegion
+			}
+			providers[key] = ProviderRegion{Provider: p.Name, DefaultRegion: region}
+		}
+	}
+	return providers
+}
+
+// resourceProviders returns the keys of the provider configurations of the module resources by their addresses
+func resourceProviders(prefix string, module ConfigurationModule, keys map[string]string) map[string]string {
+	for _, res := range module.Resources {
+		key := res.ProviderConfigKey
+		// The resources of the child modules have keys prefixed by the module, ex: network:aws
+		if i := strings.LastIndex(key, ":"); i >= 0 {
+			key = key[i+1:]
+		}
+		keys[prefix+res.Address] = key
+	}
+	for name, call := range module.ModuleCalls {
+		resourceProviders(fmt.Sprintf("%smodule.%s.", prefix, name), call.Module, keys)
+	}
+	return keys
+}
+
+// resourceProvider returns the provider of the resource and its default region, from the configuration of its
+// provider or, if it's not known, from the prefix of its type (ex: aws for aws_instance)
+func (pp ParsedProject) resourceProvider(res Resource) (schema.ProviderName, string) {
+	if key, ok := pp.ResourceProviders[indexRegex.ReplaceAllString(res.Address, "")]; ok {
+		if p, ok := pp.Providers[key]; ok {
+			return p.Provider, p.DefaultRegion
+		}
+	}
+	prefix, _, _ := strings.Cut(res.Type, "_")
+	p, ok := registry.Lookup(prefix)
+	if !ok {
+		return schema.ProviderName(prefix), ""
+	}
+	// The default configuration of the provider is used as terraform does, ex: google for google_compute_disk
+	if pr, ok := pp.Providers[prefix]; ok && pr.Provider == p.Name {
+		return pr.Provider, pr.DefaultRegion
+	}
+	return p.Name, p.DefaultRegion
+}
@@ -1,16 +1,28 @@
 package hcl
 
 import (
-	"github.com/kaytu-io/pennywise/pkg/parser/azurerm"
+	"github.com/kaytu-io/pennywise/pkg/parser/registry"
 	"github.com/kaytu-io/pennywise/pkg/schema"
 	"github.com/kaytu-io/pennywise/pkg/usage"
 )
 
 type ParsedProject struct {
-	Directory     string
-	Provider      schema.ProviderName
-	DefaultRegion string
-	RootModule    Module
+	Directory string
+	// Providers are the providers of the provider configurations by their keys, ex: aws or aws.west
+	Providers map[string]ProviderRegion
+	// ResourceProviders are the keys of the provider configurations of the resources by their addresses
+	ResourceProviders map[string]string
+	RootModule        Module
+}
+
+// newParsedProject returns the ParsedProject of the root module of the project
+func newParsedProject(directory string, project Project, rootModule Module) ParsedProject {
+	return ParsedProject{
+		Directory:         directory,
+		Providers:         projectProviders(project),
+		ResourceProviders: resourceProviders("", project.Configuration.RootModule, make(map[string]string)),
+		RootModule:        rootModule,
+	}
 }
 
 type Resource struct {
@@ -42,7 +54,7 @@ func (pp ParsedProject) buildModuleDef(module Module) schema.ModuleDef {
 		moduleDef.ChildModules = append(moduleDef.ChildModules, pp.buildModuleDef(childModule))
 	}
 	for _, resource := range module.Resources {
-		moduleDef.Resources = append(moduleDef.Resources, resource.ToResource(pp.Provider, pp.DefaultRegion))
+		moduleDef.Resources = append(moduleDef.Resources, resource.ToResource(pp.resourceProvider(resource)))
 	}
 	return moduleDef
 }
@@ -57,7 +69,7 @@ func (pp ParsedProject) GetResources() []schema.ResourceDef {
 func (pp ParsedProject) getModuleResources(module Module) []schema.ResourceDef {
 	var resources []schema.ResourceDef
 	for _, res := range module.Resources {
-		resources = append(resources, res.ToResource(pp.Provider, pp.DefaultRegion))
+		resources = append(resources, res.ToResource(pp.resourceProvider(res)))
 	}
 	for _, childModule := range module.ChildModules {
 		resources = append(resources, pp.getModuleResources(childModule)...)
@@ -66,24 +78,18 @@ func (pp ParsedProject) getModuleResources(module Module) []schema.ResourceDef {
 }
 
 func (r Resource) ToResource(provider schema.ProviderName, defaultRegion string) schema.ResourceDef {
-	var region string
-	if defaultRegion != "" {
-		region = defaultRegion
-	}
-	for key, value := range r.Values {
-		if provider == schema.AzureProvider && key == "location" {
-			region = azurerm.GetRegionCode(value.(string))
-			break
-		}
-	}
-	return schema.ResourceDef{
+	res := schema.ResourceDef{
 		Address:      r.Address,
 		Type:         r.Type,
 		Name:         r.Name,
-		RegionCode:   region,
+		RegionCode:   defaultRegion,
 		ProviderName: provider,
 		Values:       r.Values,
 	}
+	if p, ok := registry.Lookup(string(provider)); ok {
+		p.Resolve(&res)
+	}
+	return res
 }
 
 type ProviderConfig struct {
@@ -96,7 +102,19 @@ type ProviderConfig struct {
 }
 
 type Config struct {
-	ProviderConfig map[schema.ProviderName]ProviderConfig `json:"provider_config"`
+	ProviderConfig map[string]ProviderConfig `json:"provider_config"`
+	RootModule     ConfigurationModule       `json:"root_module"`
+}
+
+// ConfigurationModule is the configuration of a module, used to know the provider configurations of its resources
+type ConfigurationModule struct {
+	Resources []struct {
+		Address           string `json:"address"`
+		ProviderConfigKey string `json:"provider_config_key"`
+	} `json:"resources"`
+	ModuleCalls map[string]struct {
+		Module ConfigurationModule `json:"module"`
+	} `json:"module_calls"`
 }
 
 type Project struct {
@@ -111,5 +129,5 @@ type Project struct {
 type Module struct {
 	Address      string     `json:"address"`
 	Resources    []Resource `json:"resources"`
-	ChildModules []Module   `json:"child_modules"'`
+	ChildModules []Module   `json:"child_modules"`
 }
@@ -10,14 +10,33 @@ import (
 	"path/filepath"
 )
 
-func ParseTerragruntProject(path string, usage usagePackage.Usage) (*schema.ModuleDef, error) {
+// ParseTerragruntProject parses every terragrunt unit found on the path, each unit is returned as a child module.
+// The units inputs and dependencies outputs are evaluated by terragrunt, the var files of the opts are relative
+// to the path and are passed to every unit.
+func ParseTerragruntProject(path string, usage usagePackage.Usage, opts Options) (*schema.ModuleDef, error) {
 	runCtx, err := config.NewRunContextFromEnv(context.Background())
 	if err != nil {
 		return nil, err
 	}
+	currentDir, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	// Units are evaluated on their own working directory
+	// so the var files need to be absolute
+	var varFiles []string
+	for _, f := range opts.VarFiles {
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(currentDir, f)
+		}
+		varFiles = append(varFiles, f)
+	}
 	ctx := config.ProjectContext{
 		ProjectConfig: &config.Project{
-			Path: path,
+			Path:               path,
+			TerraformVarFiles:  varFiles,
+			TerraformWorkspace: opts.Workspace,
+			Env:                opts.Env,
 		},
 		RunContext: runCtx,
 	}
@@ -28,9 +47,16 @@ func ParseTerragruntProject(path string, usage usagePackage.Usage) (*schema.Modu
 	}
 	var projectsModule schema.ModuleDef
 	for _, dir := range dirs {
-		var rootModule Module
-		var provider schema.ProviderName
-		var defaultRegion string
+		projectName, err := filepath.Rel(currentDir, dir.ConfigDir)
+		if err != nil {
+			return nil, err
+		}
+
+		// A unit can have more than one project, each one keeps
+		// its own providers and default regions
+		unitModule := schema.ModuleDef{
+			Address: projectName,
+		}
 		jsons := dir.Provider.LoadPlanJSONs()
 		for _, j := range jsons {
 			var res Project
@@ -38,52 +64,24 @@ func ParseTerragruntProject(path string, usage usagePackage.Usage) (*schema.Modu
 			if err != nil {
 				return nil, err
 			}
-			for key, providerConfig := range res.Configuration.ProviderConfig {
-				if _, ok := map[string]bool{
-					"aws":     true,
-					"azure":   true,
-					"azurerm": true,
-				}[string(key)]; ok {
-					provider = key
-					defaultRegion = providerConfig.Expressions.Region.ConstantValue
-					break
-				}
+			for _, rootModule := range res.PlannedValues {
+				addUsageToModule(usage, &rootModule)
+				parsedProject := newParsedProject(projectName, res, rootModule)
+				projectModule := parsedProject.GetModule()
+				changeResourcesId(projectName, &projectModule)
+				unitModule.ChildModules = append(unitModule.ChildModules, projectModule.ChildModules...)
+				unitModule.Resources = append(unitModule.Resources, projectModule.Resources...)
 			}
-			for _, mod := range res.PlannedValues {
-				rootModule = mod
-			}
-		}
-
-		addUsageToModule(usage, &rootModule)
-		currentDir, err := filepath.Abs(path)
-		if err != nil {
-			return nil, err
 		}
 
-		projectName, err := filepath.Rel(currentDir, dir.ConfigDir)
-		if err != nil {
-			return nil, err
-		}
-
-		parsedProject := ParsedProject{
-			Directory:     projectName,
-			Provider:      provider,
-			DefaultRegion: defaultRegion,
-			RootModule:    rootModule,
-		}
-		projectModule := parsedProject.GetModule()
-		changeResourcesId(projectName, &projectModule)
-		projectsModule.ChildModules = append(projectsModule.ChildModules, schema.ModuleDef{
-			Address:      projectName,
-			ChildModules: projectModule.ChildModules,
-			Resources:    projectModule.Resources,
-		})
+		projectsModule.ChildModules = append(projectsModule.ChildModules, unitModule)
 	}
 	return &projectsModule, nil
 }
 
 func changeResourcesId(project string, mod *schema.ModuleDef) {
 	for i, res := range mod.Resources {
+		mod.Resources[i].UsageAddress = res.GetUsageAddress()
 		mod.Resources[i].Address = project + "." + res.Address
 	}
 	for _, childMod := range mod.ChildModules {
@@ -0,0 +1,114 @@
+package properties
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Properties are the properties of a resource (or of a nested property) of a template
+type Properties map[string]interface{}
+
+// Get returns the value of the name in the target if it's an object, nil otherwise.
+// The name is matched exactly first and then case-insensitively, as ARM property names are case-insensitive.
+func Get(target interface{}, name string) interface{} {
+	m, ok := target.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if v, ok := m[name]; ok {
+		return v
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// Value returns the value on the path of nested properties, nil if there is none
+func (p Properties) Value(path ...string) interface{} {
+	var value interface{} = map[string]interface{}(p)
+	for _, key := range path {
+		value = Get(value, key)
+		if value == nil {
+			return nil
+		}
+	}
+	return value
+}
+
+// Object returns the nested properties on the path
+func (p Properties) Object(path ...string) Properties {
+	m, ok := p.Value(path...).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return m
+}
+
+// List returns the list of nested properties on the path
+func (p Properties) List(path ...string) []Properties {
+	l, ok := p.Value(path...).([]interface{})
+	if !ok {
+		return nil
+	}
+	var list []Properties
+	for _, v := range l {
+		if m, ok := v.(map[string]interface{}); ok {
+			list = append(list, m)
+		}
+	}
+	return list
+}
+
+// StringOr returns the string value on the path or def if there is none
+func (p Properties) StringOr(def string, path ...string) string {
+	if s, ok := p.Value(path...).(string); ok && s != "" {
+		return s
+	}
+	return def
+}
+
+// Number returns the value on the path as a number, templates allow numbers to be
+// defined as strings so they are converted. It returns nil if there is no number on the path.
+func (p Properties) Number(path ...string) interface{} {
+	switch v := p.Value(path...).(type) {
+	case float64:
+		return v
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// NumberOr returns the number on the path or def if there is none
+func (p Properties) NumberOr(def float64, path ...string) interface{} {
+	if n := p.Number(path...); n != nil {
+		return n
+	}
+	return def
+}
+
+// Boolean returns the value on the path as a bool, converting it from a string if needed.
+// It returns nil if there is no boolean on the path.
+func (p Properties) Boolean(path ...string) interface{} {
+	switch v := p.Value(path...).(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return nil
+}
+
+// SetValue sets the value on the key if it's not nil
+func SetValue(values map[string]interface{}, key string, value interface{}) {
+	if value != nil {
+		values[key] = value
+	}
+}
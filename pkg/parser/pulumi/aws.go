@@ -0,0 +1,176 @@
+package pulumi
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	"github.com/kaytu-io/pennywise/pkg/usage"
+)
+
+// awsTypes are the terraform types of the Pulumi AWS resources that don't follow the
+// aws_<module>_<resource> naming, ex: aws:ec2/instance:Instance is aws_instance
+var awsTypes = map[string]string{
+	"aws:ec2/instance:Instance":                       "aws_instance",
+	"aws:ec2/eip:Eip":                                 "aws_eip",
+	"aws:ec2/natGateway:NatGateway":                   "aws_nat_gateway",
+	"aws:ec2/launchTemplate:LaunchTemplate":           "aws_launch_template",
+	"aws:ec2/launchConfiguration:LaunchConfiguration": "aws_launch_configuration",
+	"aws:ec2/dedicatedHost:DedicatedHost":             "aws_ec2_host",
+	"aws:ec2/vpc:Vpc":                                 "aws_vpc",
+	"aws:ec2/subnet:Subnet":                           "aws_subnet",
+	"aws:ec2/securityGroup:SecurityGroup":             "aws_security_group",
+	"aws:ec2/routeTable:RouteTable":                   "aws_route_table",
+	"aws:ec2/internetGateway:InternetGateway":         "aws_internet_gateway",
+	"aws:ec2/vpcEndpoint:VpcEndpoint":                 "aws_vpc_endpoint",
+	"aws:ec2/vpnConnection:VpnConnection":             "aws_vpn_connection",
+	"aws:ec2/flowLog:FlowLog":                         "aws_flow_log",
+	"aws:ec2/ami:Ami":                                 "aws_ami",
+	"aws:rds/instance:Instance":                       "aws_db_instance",
+	"aws:lb/loadBalancer:LoadBalancer":                "aws_lb",
+	"aws:alb/loadBalancer:LoadBalancer":               "aws_alb",
+	"aws:elb/loadBalancer:LoadBalancer":               "aws_elb",
+	"aws:s3/bucketV2:BucketV2":                        "aws_s3_bucket",
+	"aws:apigateway/restApi:RestApi":                  "aws_api_gateway_rest_api",
+	"aws:apigateway/stage:Stage":                      "aws_api_gateway_stage",
+	"aws:cloudwatch/eventRule:EventRule":              "aws_cloudwatch_event_rule",
+}
+
+// awsBlockNames are the Pulumi names of the nested blocks that are pluralized, the terraform block names are singular
+var awsBlockNames = map[string]string{
+	"ebs_block_devices":            "ebs_block_device",
+	"ephemeral_block_devices":      "ephemeral_block_device",
+	"network_interfaces":           "network_interface",
+	"global_secondary_indexes":     "global_secondary_index",
+	"local_secondary_indexes":      "local_secondary_index",
+	"attributes":                   "attribute",
+	"replicas":                     "replica",
+	"subnet_mappings":              "subnet_mapping",
+	"lifecycle_rules":              "lifecycle_rule",
+	"origins":                      "origin",
+	"ordered_cache_behaviors":      "ordered_cache_behavior",
+	"capacity_provider_strategies": "capacity_provider_strategy",
+	"ordered_placement_strategies": "ordered_placement_strategy",
+	"placement_constraints":        "placement_constraint",
+	"load_balancers":               "load_balancer",
+	"rules":                        "rule",
+}
+
+// awsMapAttributes are the object attributes that are maps in terraform, they are not converted to blocks
+// and their keys are kept as they are
+var awsMapAttributes = map[string]bool{
+	"tags":       true,
+	"tags_all":   true,
+	"variables":  true,
+	"parameters": true,
+}
+
+// awsResourceType returns the terraform type of a Pulumi AWS resource type, ex: aws:sqs/queue:Queue is aws_sqs_queue
+func awsResourceType(pulumiType string) string {
+	if t, ok := awsTypes[pulumiType]; ok {
+		return t
+	}
+	// aws:<module>/<resource>:<Resource>
+	parts := strings.Split(pulumiType, ":")
+	if len(parts) != 3 {
+		return ""
+	}
+	module, resource, _ := strings.Cut(parts[1], "/")
+	resource = snakeCase(resource)
+	if module == "ec2" || strings.HasPrefix(resource, module+"_") {
+		return "aws_" + resource
+	}
+	return fmt.Sprintf("aws_%s_%s", module, resource)
+}
+
+// awsResources returns the terraform resource equivalent to a Pulumi AWS resource, the inputs are
+// converted to the terraform attributes
+func awsResources(state State, name, region, moduleAddress string, u usage.Usage) ([]schema.ResourceDef, bool) {
+	rType := awsResourceType(state.Type)
+	if rType == "" {
+		return nil, false
+	}
+	address := fmt.Sprintf("%s.%s", rType, name)
+	if moduleAddress != "" {
+		address = moduleAddress + "." + address
+	}
+	values := awsValues(state.Inputs)
+	values[usage.Key] = u.GetUsage(rType, address)
+	return []schema.ResourceDef{{
+		Address:      address,
+		Type:         rType,
+		Name:         name,
+		RegionCode:   region,
+		ProviderName: schema.AWSProvider,
+		Values:       values,
+	}}, true
+}
+
+// awsValues converts the Pulumi inputs to terraform attributes: the keys are converted to snake case
+// and the nested objects to blocks (lists of one object) as in the terraform plans
+func awsValues(inputs map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	for key, value := range inputs {
+		attribute := snakeCase(key)
+		if attribute == "tags" {
+			// The tags of the autoscaling groups are a list of blocks named tag
+			if _, ok := value.([]interface{}); ok {
+				attribute = "tag"
+			}
+		}
+		if block, ok := awsBlockNames[attribute]; ok {
+			attribute = block
+		}
+		if awsMapAttributes[attribute] {
+			values[attribute] = knownValue(value)
+			continue
+		}
+		values[attribute] = awsValue(value, true)
+	}
+	return values
+}
+
+// awsValue converts an input value, the objects that are the value of an attribute are blocks
+func awsValue(value interface{}, attribute bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if attribute {
+			return []interface{}{awsValues(v)}
+		}
+		return awsValues(v)
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			result = append(result, awsValue(item, false))
+		}
+		return result
+	}
+	return knownValue(value)
+}
+
+// knownValue returns nil for the values that are unknown during the preview
+func knownValue(value interface{}) interface{} {
+	if s, ok := value.(string); ok && s == unknownValue {
+		return nil
+	}
+	return value
+}
+
+// snakeCase converts a camel case name to snake case, ex: instanceType to instance_type
+func snakeCase(name string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Acronyms are kept together, ex: HTTPEndpoint to http_endpoint
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) && runes[i-1] != '_' {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,82 @@
+package pulumi
+
+import (
+	"strings"
+
+	"github.com/kaytu-io/pennywise/pkg/parser/arm"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	"github.com/kaytu-io/pennywise/pkg/usage"
+)
+
+// azureNativeTypes are the ARM types of the Azure Native resources keyed by <module>:<resource>.
+// Azure Native resources have the inputs of the ARM resources so they are mapped with the ARM mappers.
+var azureNativeTypes = map[string]string{
+	"compute:VirtualMachine":               "Microsoft.Compute/virtualMachines",
+	"compute:VirtualMachineScaleSet":       "Microsoft.Compute/virtualMachineScaleSets",
+	"compute:Disk":                         "Microsoft.Compute/disks",
+	"compute:Snapshot":                     "Microsoft.Compute/snapshots",
+	"compute:Image":                        "Microsoft.Compute/images",
+	"compute:AvailabilitySet":              "Microsoft.Compute/availabilitySets",
+	"network:LoadBalancer":                 "Microsoft.Network/loadBalancers",
+	"network:PublicIPAddress":              "Microsoft.Network/publicIPAddresses",
+	"network:PublicIPPrefix":               "Microsoft.Network/publicIPPrefixes",
+	"network:NatGateway":                   "Microsoft.Network/natGateways",
+	"network:ApplicationGateway":           "Microsoft.Network/applicationGateways",
+	"network:VirtualNetworkGateway":        "Microsoft.Network/virtualNetworkGateways",
+	"network:PrivateEndpoint":              "Microsoft.Network/privateEndpoints",
+	"network:VirtualNetwork":               "Microsoft.Network/virtualNetworks",
+	"network:Subnet":                       "Microsoft.Network/virtualNetworks/subnets",
+	"network:NetworkInterface":             "Microsoft.Network/networkInterfaces",
+	"network:NetworkSecurityGroup":         "Microsoft.Network/networkSecurityGroups",
+	"network:RouteTable":                   "Microsoft.Network/routeTables",
+	"storage:StorageAccount":               "Microsoft.Storage/storageAccounts",
+	"web:AppServicePlan":                   "Microsoft.Web/serverfarms",
+	"containerservice:ManagedCluster":      "Microsoft.ContainerService/managedClusters",
+	"containerservice:AgentPool":           "Microsoft.ContainerService/managedClusters/agentPools",
+	"containerregistry:Registry":           "Microsoft.ContainerRegistry/registries",
+	"sql:Server":                           "Microsoft.Sql/servers",
+	"sql:Database":                         "Microsoft.Sql/servers/databases",
+	"dbforpostgresql:Server":               "Microsoft.DBforPostgreSQL/flexibleServers",
+	"dbformysql:Server":                    "Microsoft.DBforMySQL/flexibleServers",
+	"keyvault:Vault":                       "Microsoft.KeyVault/vaults",
+	"managedidentity:UserAssignedIdentity": "Microsoft.ManagedIdentity/userAssignedIdentities",
+	"authorization:RoleAssignment":         "Microsoft.Authorization/roleAssignments",
+}
+
+// azureNativeResources returns the terraform resources equivalent to an Azure Native resource
+func azureNativeResources(state State, name, location, moduleAddress string, u usage.Usage) ([]schema.ResourceDef, bool) {
+	// azure-native:<module>[/<version>]:<Resource>
+	parts := strings.Split(state.Type, ":")
+	if len(parts) != 3 {
+		return nil, false
+	}
+	module, _, _ := strings.Cut(parts[1], "/")
+	armType, ok := azureNativeTypes[module+":"+parts[2]]
+	if !ok {
+		return nil, false
+	}
+
+	// The properties of the ARM resource are inputs of the Azure Native resources, except for
+	// the few resources that have a properties input
+	props := make(map[string]interface{})
+	for key, value := range state.Inputs {
+		props[key] = knownValue(value)
+	}
+	if p, ok := state.Inputs["properties"].(map[string]interface{}); ok {
+		for key, value := range p {
+			props[key] = knownValue(value)
+		}
+	}
+	if l, ok := state.Inputs["location"].(string); ok && l != unknownValue {
+		location = l
+	}
+	res := map[string]interface{}{
+		"type":       armType,
+		"name":       name,
+		"location":   location,
+		"sku":        state.Inputs["sku"],
+		"kind":       state.Inputs["kind"],
+		"properties": props,
+	}
+	return arm.ResourceDefs(res, moduleAddress, u)
+}
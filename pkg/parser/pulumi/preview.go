@@ -0,0 +1,212 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kaytu-io/pennywise/pkg/schema"
+	"github.com/kaytu-io/pennywise/pkg/usage"
+)
+
+// unknownValue is the value of the outputs that are unknown during the preview
+const unknownValue = "04da6b54-80e4-46f7-96ec-b56ff0331ba9"
+
+const (
+	stackType     = "pulumi:pulumi:Stack"
+	providersType = "pulumi:providers:"
+)
+
+// Preview is the output of pulumi preview --json
+type Preview struct {
+	Config map[string]interface{} `json:"config"`
+	Steps  []Step                 `json:"steps"`
+}
+
+// Step is an operation of the preview on a resource
+type Step struct {
+	Op       string `json:"op"`
+	URN      string `json:"urn"`
+	NewState *State `json:"newState"`
+}
+
+// State is the state of a resource
+type State struct {
+	URN      string                 `json:"urn"`
+	Custom   bool                   `json:"custom"`
+	Type     string                 `json:"type"`
+	Inputs   map[string]interface{} `json:"inputs"`
+	Parent   string                 `json:"parent"`
+	Provider string                 `json:"provider"`
+}
+
+// ReadPreview reads the output of pulumi preview --json on the path
+func ReadPreview(path string) (*Preview, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read preview %s: %w", path, err)
+	}
+	var preview Preview
+	if err := json.Unmarshal(content, &preview); err != nil {
+		return nil, fmt.Errorf("failed to parse preview %s: %w", path, err)
+	}
+	return &preview, nil
+}
+
+// Stack returns the name of the stack of the preview
+func (p *Preview) Stack() string {
+	for _, step := range p.Steps {
+		// urn:pulumi:<stack>::<project>::<type>::<name>
+		if parts := strings.SplitN(strings.TrimPrefix(step.URN, "urn:pulumi:"), "::", 2); len(parts) == 2 {
+			return parts[0]
+		}
+	}
+	return ""
+}
+
+// states returns the state of the resources after the changes of the preview, the deleted resources
+// and the resources that are only read are excluded
+func (p *Preview) states() []State {
+	var states []State
+	seen := make(map[string]bool)
+	for _, step := range p.Steps {
+		switch step.Op {
+		case "delete", "delete-replaced", "discard", "discard-replaced", "read", "read-replacement", "remove-pending-replace":
+			continue
+		}
+		if step.NewState == nil || seen[step.URN] {
+			continue
+		}
+		seen[step.URN] = true
+		state := *step.NewState
+		if state.URN == "" {
+			state.URN = step.URN
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// GetModule returns the resources of the preview as a module, the component resources are child modules.
+// The types of the resources of other providers than AWS and Azure Native are returned as unsupported.
+func (p *Preview) GetModule(u usage.Usage) (*schema.ModuleDef, []string) {
+	states := p.states()
+
+	// The region of the explicit providers, the resources using the default providers use the stack config
+	providerRegions := make(map[string]string)
+	for _, state := range states {
+		if strings.HasPrefix(state.Type, providersType) {
+			region, _ := state.Inputs["region"].(string)
+			if region == "" {
+				region, _ = state.Inputs["location"].(string)
+			}
+			providerRegions[state.URN] = region
+		}
+	}
+
+	root := &schema.ModuleDef{}
+	modules := make(map[string]*schema.ModuleDef)
+	var moduleOrder []string
+	for _, state := range states {
+		if !state.Custom && state.Type != stackType {
+			modules[state.URN] = &schema.ModuleDef{}
+			moduleOrder = append(moduleOrder, state.URN)
+		}
+	}
+	for _, urn := range moduleOrder {
+		modules[urn].Address = p.moduleAddress(urn, states)
+	}
+
+	var unsupported []string
+	seenUnsupported := make(map[string]bool)
+	for _, state := range states {
+		if !state.Custom || strings.HasPrefix(state.Type, providersType) {
+			continue
+		}
+		module := root
+		if parent, ok := modules[state.Parent]; ok {
+			module = parent
+		}
+		provider, _, _ := strings.Cut(state.Type, ":")
+		region := providerRegions[providerURN(state.Provider)]
+		var resources []schema.ResourceDef
+		var ok bool
+		switch provider {
+		case "aws":
+			if region == "" {
+				region = p.config("aws:region")
+			}
+			resources, ok = awsResources(state, urnName(state.URN), region, module.Address, u)
+		case "azure-native":
+			if region == "" {
+				region = p.config("azure-native:location")
+			}
+			resources, ok = azureNativeResources(state, urnName(state.URN), region, module.Address, u)
+		}
+		if !ok {
+			if !seenUnsupported[state.Type] {
+				seenUnsupported[state.Type] = true
+				unsupported = append(unsupported, state.Type)
+			}
+			continue
+		}
+		module.Resources = append(module.Resources, resources...)
+	}
+
+	// Components are added to their parent component from the innermost ones, as the
+	// modules are copied when they are added
+	for i := len(moduleOrder) - 1; i >= 0; i-- {
+		urn := moduleOrder[i]
+		parent := root
+		for _, state := range states {
+			if state.URN == urn {
+				if m, ok := modules[state.Parent]; ok {
+					parent = m
+				}
+				break
+			}
+		}
+		parent.ChildModules = append([]schema.ModuleDef{*modules[urn]}, parent.ChildModules...)
+	}
+	return root, unsupported
+}
+
+// moduleAddress returns the address of the module of a component, the names of its parent components joined by dots
+func (p *Preview) moduleAddress(urn string, states []State) string {
+	var names []string
+	for urn != "" {
+		var parent *State
+		for i := range states {
+			if states[i].URN == urn {
+				parent = &states[i]
+				break
+			}
+		}
+		if parent == nil || parent.Custom || parent.Type == stackType {
+			break
+		}
+		names = append([]string{urnName(urn)}, names...)
+		urn = parent.Parent
+	}
+	return strings.Join(names, ".")
+}
+
+// config returns the value of the stack config key
+func (p *Preview) config(key string) string {
+	v, _ := p.Config[key].(string)
+	return v
+}
+
+// urnName returns the name of the resource of the URN
+func urnName(urn string) string {
+	return urn[strings.LastIndex(urn, "::")+2:]
+}
+
+// providerURN returns the URN of a provider reference, references are the URN and the ID of the provider joined by ::
+func providerURN(reference string) string {
+	if i := strings.LastIndex(reference, "::"); i > 0 && strings.Count(reference, "::") > 3 {
+		return reference[:i]
+	}
+	return reference
+}
@@ -0,0 +1,56 @@
+package registry
+
+import (
+	"github.com/kaytu-io/pennywise/pkg/diagnostic"
+	"github.com/kaytu-io/pennywise/pkg/parser/aws"
+	"github.com/kaytu-io/pennywise/pkg/parser/azurerm"
+	"github.com/kaytu-io/pennywise/pkg/parser/azurerm/location"
+	"github.com/kaytu-io/pennywise/pkg/parser/google"
+	gcpregion "github.com/kaytu-io/pennywise/pkg/parser/google/region"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+)
+
+// builtin are the providers priced by the server
+var builtin = []Provider{
+	{
+		Name:          schema.AWSProvider,
+		Initializer:   aws.TerraformProviderInitializer,
+		DefaultRegion: aws.DefaultRegion,
+	},
+	{
+		Name:        schema.AzureProvider,
+		Initializer: azurerm.TerraformProviderInitializer,
+		// azure is the provider key of the HCL projects of the Azure resources
+		Aliases: []string{"azure"},
+		Region:  azureLocation,
+	},
+	{
+		Name:          schema.GCPProvider,
+		Initializer:   google.TerraformProviderInitializer,
+		DefaultRegion: google.DefaultRegion,
+		Region: func(values map[string]interface{}, defaultRegion string) (string, *diagnostic.Diagnostic) {
+			return gcpregion.FromValues(values, defaultRegion), nil
+		},
+	},
+}
+
+func init() {
+	for _, p := range builtin {
+		if err := Register(p); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// azureLocation returns the normalized location of the Azure resources (ex: westeurope for West Europe)
+func azureLocation(values map[string]interface{}, defaultRegion string) (string, *diagnostic.Diagnostic) {
+	loc, ok := values["location"].(string)
+	if !ok {
+		return defaultRegion, nil
+	}
+	region, err := location.Normalize(loc)
+	if err != nil {
+		return "", &diagnostic.Diagnostic{Kind: diagnostic.InvalidValue, Attribute: "location", Message: err.Error()}
+	}
+	return region, nil
+}
@@ -0,0 +1,81 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kaytu-io/pennywise/pkg/diagnostic"
+	"github.com/kaytu-io/pennywise/pkg/parser/terraform"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+)
+
+// Manifest is the definition of a provider in a json file, used to register the providers
+// that are not built in (ex: datadog) without changing the parsers
+type Manifest struct {
+	// Name is the name of the provider the resources are submitted with
+	Name string `json:"name"`
+	// MatchNames are the names of the provider configurations and sources besides its name, ex: registry.terraform.io/datadog/datadog
+	MatchNames []string `json:"match_names"`
+	// DefaultRegion is the region of the resources when the provider configuration has none
+	DefaultRegion string `json:"default_region"`
+	// RegionAttributes are the attributes of the resources that hold their region, the first one set is used
+	RegionAttributes []string `json:"region_attributes"`
+}
+
+// manifestProvider is the terraform.Provider of the providers defined by a Manifest
+type manifestProvider struct {
+	name string
+}
+
+// Name returns the name of the provider
+func (p manifestProvider) Name() string { return p.name }
+
+// Provider returns the Provider defined by the manifest
+func (m Manifest) Provider() Provider {
+	p := Provider{
+		Name: schema.ProviderName(m.Name),
+		Initializer: terraform.ProviderInitializer{
+			MatchNames: append([]string{m.Name}, m.MatchNames...),
+			Provider: func(values map[string]interface{}) (terraform.Provider, error) {
+				return manifestProvider{name: m.Name}, nil
+			},
+		},
+		DefaultRegion: m.DefaultRegion,
+	}
+	if len(m.RegionAttributes) > 0 {
+		p.Region = func(values map[string]interface{}, defaultRegion string) (string, *diagnostic.Diagnostic) {
+			for _, attribute := range m.RegionAttributes {
+				if r, ok := values[attribute].(string); ok && r != "" {
+					return r, nil
+				}
+			}
+			return defaultRegion, nil
+		}
+	}
+	return p
+}
+
+// LoadManifests registers the providers defined by the json files of the directory, nothing
+// is registered if the directory doesn't exist
+func LoadManifests(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var m Manifest
+		if err := json.Unmarshal(content, &m); err != nil {
+			return fmt.Errorf("failed to parse provider manifest %s: %w", path, err)
+		}
+		if err := Register(m.Provider()); err != nil {
+			return fmt.Errorf("failed to register provider of manifest %s: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kaytu-io/pennywise/pkg/diagnostic"
+	"github.com/kaytu-io/pennywise/pkg/parser/terraform"
+	"github.com/kaytu-io/pennywise/pkg/schema"
+)
+
+// RegionFunc returns the region of a resource from its values (ex: the location of the Azure resources), the
+// defaultRegion is the region of the provider. The diagnostic is returned if the region of the resource is not valid.
+type RegionFunc func(values map[string]interface{}, defaultRegion string) (string, *diagnostic.Diagnostic)
+
+// Provider is a provider whose resources can be estimated, it's used by the parsers of the terraform plans
+// and projects to know the providers of the resources and their regions.
+type Provider struct {
+	// Name is the name of the provider the resources are submitted with, ex: google
+	Name schema.ProviderName
+	// Initializer initializes the provider from its configuration on the terraform plans, its MatchNames are
+	// the names of the provider configurations and sources (ex: google-beta or registry.terraform.io/hashicorp/google)
+	Initializer terraform.ProviderInitializer
+	// Aliases are other names the provider is looked up with, ex: azure for azurerm
+	Aliases []string
+	// DefaultRegion is the region of the resources when the provider configuration has none
+	DefaultRegion string
+	// Region returns the region of a resource, the resources are in the region of the provider if it's nil
+	Region RegionFunc
+}
+
+// Resolve sets the provider name on the resource and its region from its values, the region is not
+// validated if there is already a diagnostic on its attribute (ex: the location is an unresolved reference)
+func (p Provider) Resolve(res *schema.ResourceDef) {
+	res.ProviderName = p.Name
+	if p.Region == nil {
+		return
+	}
+	region, d := p.Region(res.Values, res.RegionCode)
+	if d != nil {
+		if !hasDiagnostic(res.Diagnostics, d.Attribute) {
+			res.Diagnostics = append(res.Diagnostics, *d)
+		}
+		return
+	}
+	res.RegionCode = region
+}
+
+// providers are the registered providers by their names and match names
+var providers = make(map[string]Provider)
+
+// Register adds the provider to the registry, it has to be called before parsing (ex: from an init function).
+// It fails if the provider has no name or if one of its names is already registered, so the
+// builtin providers can't be replaced.
+func Register(p Provider) error {
+	if p.Name == "" {
+		return fmt.Errorf("provider has no name")
+	}
+	names := append([]string{string(p.Name)}, p.Initializer.MatchNames...)
+	names = append(names, p.Aliases...)
+	for _, name := range names {
+		if registered, ok := providers[strings.ToLower(name)]; ok {
+			return fmt.Errorf("name %q of provider %q is already registered by provider %q", name, p.Name, registered.Name)
+		}
+	}
+	for _, name := range names {
+		providers[strings.ToLower(name)] = p
+	}
+	return nil
+}
+
+// Lookup returns the provider registered with the name, the name can be the name of the provider, one of its
+// match names, the name of its configuration with an alias (ex: aws.west) or its source (ex: registry.terraform.io/hashicorp/aws),
+// the sources of other registries only match the official providers of the hashicorp and opentofu namespaces
+func Lookup(name string) (Provider, bool) {
+	name = strings.ToLower(name)
+	if p, ok := providers[name]; ok {
+		return p, true
+	}
+	if i := strings.Index(name, "."); i > 0 && !strings.Contains(name, "/") {
+		if p, ok := providers[name[:i]]; ok {
+			return p, true
+		}
+	}
+	source := terraform.ParseProviderSource(name)
+	if source.Namespace != "hashicorp" && source.Namespace != "opentofu" {
+		return Provider{}, false
+	}
+	p, ok := providers[source.Type]
+	return p, ok
+}
+
+// All returns the registered providers sorted by name
+func All() []Provider {
+	seen := make(map[schema.ProviderName]bool)
+	var all []Provider
+	for _, p := range providers {
+		if !seen[p.Name] {
+			seen[p.Name] = true
+			all = append(all, p)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// Initializers returns the initializers of the registered providers used to read the terraform plans
+func Initializers() []terraform.ProviderInitializer {
+	var initializers []terraform.ProviderInitializer
+	for _, p := range All() {
+		if p.Initializer.Provider != nil {
+			initializers = append(initializers, p.Initializer)
+		}
+	}
+	return initializers
+}
+
+// hasDiagnostic returns true if there is a diagnostic on the attribute
+func hasDiagnostic(diagnostics []diagnostic.Diagnostic, attribute string) bool {
+	for _, d := range diagnostics {
+		if d.Attribute == attribute {
+			return true
+		}
+	}
+	return false
+}
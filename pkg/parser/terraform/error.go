@@ -1,6 +1,11 @@
 package terraform
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kaytu-io/pennywise/pkg/diagnostic"
+)
 
 // Errors that might be returned from procesing the HCL
 var (
@@ -8,3 +13,35 @@ var (
 	ErrNoKnownProvider = errors.New("terraform providers are not yet supported")
 	ErrNoProviders     = errors.New("no valid providers found")
 )
+
+// VariableNotDefinedError is returned when an expression references a variable without value
+type VariableNotDefinedError struct {
+	Name string
+}
+
+func (e *VariableNotDefinedError) Error() string {
+	return fmt.Sprintf("required variable %q is not defined", e.Name)
+}
+
+// UnsupportedExpressionError is returned when an expression can't be evaluated from the plan
+type UnsupportedExpressionError struct {
+	Reason string
+}
+
+func (e *UnsupportedExpressionError) Error() string {
+	return e.Reason
+}
+
+// asDiagnostic returns the diagnostic of the attribute for the errors that don't prevent
+// the resource from being estimated
+func asDiagnostic(attribute string, err error) (diagnostic.Diagnostic, bool) {
+	var variableErr *VariableNotDefinedError
+	if errors.As(err, &variableErr) {
+		return diagnostic.Diagnostic{Kind: diagnostic.MissingVariable, Attribute: attribute, Message: variableErr.Error()}, true
+	}
+	var unsupportedErr *UnsupportedExpressionError
+	if errors.As(err, &unsupportedErr) {
+		return diagnostic.Diagnostic{Kind: diagnostic.UnsupportedExpression, Attribute: attribute, Message: unsupportedErr.Error()}, true
+	}
+	return diagnostic.Diagnostic{}, false
+}
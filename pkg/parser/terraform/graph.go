@@ -0,0 +1,431 @@
+package terraform
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// refMarker prefixes the values that are references to other resources, data sources or modules
+	// and still need to be resolved through the referenceGraph
+	refMarker = "*ref*"
+	// maxReferenceDepth limits how many references are followed when resolving a chain of
+	// module inputs and outputs, so a cyclic configuration can not loop forever
+	maxReferenceDepth = 20
+)
+
+// referenceGraph links the addresses of resources, data sources, module outputs and module inputs to the
+// values they expose so references between them can be resolved before pricing.
+type referenceGraph struct {
+	// planned holds the values of every resource and data source instance from the planned values keyed by
+	// the instance address (ex: module.a[0].aws_instance.web[0]), bases maps an address without any index
+	// (ex: module.a.aws_instance.web) to the addresses of all its instances in order
+	planned map[string]map[string]interface{}
+	bases   map[string][]string
+
+	// prior holds the same information as planned but from the prior state, it is only used when an address
+	// or an attribute is not known on the planned values (ex: data sources or computed attributes)
+	prior      map[string]map[string]interface{}
+	priorBases map[string][]string
+
+	// expressions holds the evaluated value of module outputs (module.a.output_name) and module inputs
+	// (module.a.var.input_name), the value can itself contain references
+	expressions map[string]interface{}
+
+	// ambiguous holds the references that match several instances whose values differ, with the addresses
+	// of these instances
+	ambiguous map[string][]string
+}
+
+// newReferenceGraph returns an empty referenceGraph
+func newReferenceGraph() *referenceGraph {
+	return &referenceGraph{
+		planned:     make(map[string]map[string]interface{}),
+		bases:       make(map[string][]string),
+		prior:       make(map[string]map[string]interface{}),
+		priorBases:  make(map[string][]string),
+		expressions: make(map[string]interface{}),
+		ambiguous:   make(map[string][]string),
+	}
+}
+
+// addPlannedResources adds the resources of the resourcesMap to the graph, the values are shared with the
+// resources so references resolved on them are also visible through the graph
+func (g *referenceGraph) addPlannedResources(resourcesMap map[string][]Resource) {
+	for _, resources := range resourcesMap {
+		for _, res := range resources {
+			g.addInstance(g.planned, g.bases, res)
+		}
+	}
+}
+
+// addPriorModule adds the resources of the module and all its children from the prior state
+func (g *referenceGraph) addPriorModule(module *Module) {
+	for _, res := range module.Resources {
+		g.addInstance(g.prior, g.priorBases, res)
+	}
+	for _, child := range module.ChildModules {
+		g.addPriorModule(child)
+	}
+}
+
+func (g *referenceGraph) addInstance(instances map[string]map[string]interface{}, bases map[string][]string, res Resource) {
+	if _, ok := instances[res.Address]; ok {
+		return
+	}
+	instances[res.Address] = res.Values
+	base := stripInstanceKeys(res.Address)
+	bases[base] = append(bases[base], res.Address)
+}
+
+// setExpression sets the value of a module output or input on the given address
+func (g *referenceGraph) setExpression(address string, value interface{}) {
+	g.expressions[address] = value
+}
+
+// resolve returns the value of the reference ref (without the refMarker), the second value
+// is false if the reference points to something unknown or not yet resolved
+func (g *referenceGraph) resolve(ref string) (interface{}, bool) {
+	return g.resolveDepth(ref, 0)
+}
+
+func (g *referenceGraph) resolveDepth(ref string, depth int) (interface{}, bool) {
+	if depth > maxReferenceDepth {
+		return nil, false
+	}
+	parts := splitReference(ref)
+	for i := len(parts); i > 0; i-- {
+		addr := joinReference(parts[:i])
+		attrs := parts[i:]
+
+		if value, ok := g.expression(addr); ok {
+			value, ok = g.resolveValue(value, depth+1)
+			if !ok {
+				return nil, false
+			}
+			return lookupAttribute(value, attrs)
+		}
+
+		if len(attrs) == 0 {
+			continue
+		}
+		if v, found, ok := g.lookup(g.planned, g.bases, addr, attrs); found {
+			if ok {
+				return v, true
+			}
+			if v, _, ok := g.lookup(g.prior, g.priorBases, addr, attrs); ok {
+				return v, true
+			}
+			return nil, false
+		}
+		if v, found, ok := g.lookup(g.prior, g.priorBases, addr, attrs); found {
+			return v, ok
+		}
+	}
+	return nil, false
+}
+
+// expression returns the value of the module output or input on the address. The address can have the keys of the
+// module instances (ex: module.a[0].output_name for the output of module.a), the references of the value are then
+// qualified with the module instance they are made from so they resolve to its sibling instances.
+func (g *referenceGraph) expression(addr string) (interface{}, bool) {
+	if value, ok := g.expressions[addr]; ok {
+		return value, true
+	}
+	prefix := modulePrefix(addr)
+	configPrefix := stripInstanceKeys(prefix)
+	if prefix == configPrefix {
+		return nil, false
+	}
+	name := strings.TrimPrefix(addr, prefix)
+	value, ok := g.expressions[configPrefix+name]
+	if !ok {
+		return nil, false
+	}
+	// The inputs of a module are evaluated in its parent module
+	if strings.HasPrefix(name, ".var.") {
+		prefix = parentModule(prefix)
+		configPrefix = stripInstanceKeys(prefix)
+	}
+	return qualifyInstanceReferences(value, configPrefix, prefix), true
+}
+
+// lookup returns the attribute of the instances on the address, found is false if no instance is on the address.
+// An address without some of the indexes of the instances (ex: module.a.aws_instance.web for the instances of a
+// counted module) matches all of them, the attribute is only returned if it has the same value on every instance
+// and the address is recorded as ambiguous otherwise.
+func (g *referenceGraph) lookup(instances map[string]map[string]interface{}, bases map[string][]string, addr string, attrs []string) (value interface{}, found bool, ok bool) {
+	if values, ok := instances[addr]; ok {
+		v, ok := lookupAttribute(values, attrs)
+		return v, true, ok && !isReference(v)
+	}
+	var matches []string
+	for _, instance := range bases[stripInstanceKeys(addr)] {
+		if matchesInstance(addr, instance) {
+			matches = append(matches, instance)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, false, false
+	}
+	for i, instance := range matches {
+		v, ok := lookupAttribute(instances[instance], attrs)
+		if !ok || isReference(v) {
+			return nil, true, false
+		}
+		if i > 0 && !reflect.DeepEqual(v, value) {
+			g.ambiguous[joinReference(append(splitReference(addr), attrs...))] = matches
+			return nil, true, false
+		}
+		value = v
+	}
+	return value, true, true
+}
+
+// ambiguousInstances returns the instances matched by the reference if they have different values
+func (g *referenceGraph) ambiguousInstances(ref string) ([]string, bool) {
+	instances, ok := g.ambiguous[joinReference(splitReference(ref))]
+	return instances, ok
+}
+
+// resolveValue resolves all the references found on the value, the second value is false
+// if any of them could not be resolved
+func (g *referenceGraph) resolveValue(value interface{}, depth int) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		if !isReference(v) {
+			return v, true
+		}
+		return g.resolveDepth(strings.TrimPrefix(v, refMarker+"."), depth)
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(v))
+		for k, iv := range v {
+			rv, ok := g.resolveValue(iv, depth)
+			if !ok {
+				return nil, false
+			}
+			resolved[k] = rv
+		}
+		return resolved, true
+	case []interface{}:
+		resolved := make([]interface{}, 0, len(v))
+		for _, iv := range v {
+			rv, ok := g.resolveValue(iv, depth)
+			if !ok {
+				return nil, false
+			}
+			resolved = append(resolved, rv)
+		}
+		return resolved, true
+	default:
+		return v, true
+	}
+}
+
+// lookupAttribute walks the value following the attribute path, parts can be
+// attribute names or index keys like [0] or ["key"]
+func lookupAttribute(value interface{}, attrs []string) (interface{}, bool) {
+	for _, attr := range attrs {
+		if value == nil {
+			return nil, false
+		}
+		if strings.HasPrefix(attr, "[") {
+			key := strings.Trim(attr, "[]")
+			switch v := value.(type) {
+			case []interface{}:
+				index, err := strconv.Atoi(key)
+				if err != nil || index < 0 || index >= len(v) {
+					return nil, false
+				}
+				value = v[index]
+			case map[string]interface{}:
+				iv, ok := v[strings.Trim(key, `"`)]
+				if !ok {
+					return nil, false
+				}
+				value = iv
+			default:
+				return nil, false
+			}
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			iv, ok := v[attr]
+			if !ok {
+				return nil, false
+			}
+			value = iv
+		case []interface{}:
+			// Nested blocks are represented as lists with one element
+			// so we look up the attribute on the first one
+			if len(v) == 0 {
+				return nil, false
+			}
+			m, ok := v[0].(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			iv, ok := m[attr]
+			if !ok {
+				return nil, false
+			}
+			value = iv
+		default:
+			return nil, false
+		}
+	}
+	if value == nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// splitReference splits a reference into its parts separated by dots, index keys are returned as
+// separate parts (ex: aws_instance.web[0].id => aws_instance, web, [0], id)
+func splitReference(ref string) []string {
+	var parts []string
+	var current strings.Builder
+	var inBrackets, inQuotes bool
+	flush := func() {
+		if current.Len() > 0 {
+			parts = append(parts, current.String())
+			current.Reset()
+		}
+	}
+	for _, c := range ref {
+		switch {
+		case c == '"' && inBrackets:
+			inQuotes = !inQuotes
+			current.WriteRune(c)
+		case inQuotes:
+			current.WriteRune(c)
+		case c == '[':
+			flush()
+			inBrackets = true
+			current.WriteRune(c)
+		case c == ']':
+			current.WriteRune(c)
+			inBrackets = false
+			flush()
+		case c == '.' && !inBrackets:
+			flush()
+		default:
+			current.WriteRune(c)
+		}
+	}
+	flush()
+	return parts
+}
+
+// joinReference is the reverse of splitReference
+func joinReference(parts []string) string {
+	var sb strings.Builder
+	for i, p := range parts {
+		if i > 0 && !strings.HasPrefix(p, "[") {
+			sb.WriteString(".")
+		}
+		sb.WriteString(p)
+	}
+	return sb.String()
+}
+
+// stripInstanceKeys removes the instance keys of the modules and the resource from an address
+// (ex: module.a[0].aws_instance.web["a"] => module.a.aws_instance.web)
+func stripInstanceKeys(addr string) string {
+	var parts []string
+	for _, part := range splitReference(addr) {
+		if !strings.HasPrefix(part, "[") {
+			parts = append(parts, part)
+		}
+	}
+	return joinReference(parts)
+}
+
+// matchesInstance returns true if the address matches the instance address, the indexes of the address have to
+// be the ones of the instance but the address can omit some of them (ex: module.a.aws_instance.web[1] matches
+// module.a[0].aws_instance.web[1])
+func matchesInstance(addr, instance string) bool {
+	addrParts := splitReference(addr)
+	var i int
+	for _, part := range splitReference(instance) {
+		if i < len(addrParts) && addrParts[i] == part {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(part, "[") {
+			return false
+		}
+	}
+	return i == len(addrParts)
+}
+
+// modulePrefix returns the address of the module instance of the address, ex: module.a[0] for
+// module.a[0].aws_instance.web[1], it's empty for the root module
+func modulePrefix(addr string) string {
+	parts := splitReference(addr)
+	var i int
+	for i+1 < len(parts) && parts[i] == "module" {
+		i += 2
+		if i < len(parts) && strings.HasPrefix(parts[i], "[") {
+			i++
+		}
+	}
+	return joinReference(parts[:i])
+}
+
+// parentModule returns the address of the parent module instance of the module instance, ex: module.a[0] for
+// module.a[0].module.b["x"]
+func parentModule(prefix string) string {
+	parts := splitReference(prefix)
+	if len(parts) > 0 && strings.HasPrefix(parts[len(parts)-1], "[") {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) < 2 {
+		return ""
+	}
+	return joinReference(parts[:len(parts)-2])
+}
+
+// qualifyInstanceReferences returns a copy of the value with the references made from inside the module configPrefix
+// (ex: module.a) qualified with the module instance (ex: module.a[0])
+func qualifyInstanceReferences(value interface{}, configPrefix, instancePrefix string) interface{} {
+	switch v := value.(type) {
+	case string:
+		if isReference(v) && strings.HasPrefix(v, refMarker+"."+configPrefix+".") {
+			return refMarker + "." + instancePrefix + strings.TrimPrefix(v, refMarker+"."+configPrefix)
+		}
+		return v
+	case map[string]interface{}:
+		qualified := make(map[string]interface{}, len(v))
+		for k, iv := range v {
+			qualified[k] = qualifyInstanceReferences(iv, configPrefix, instancePrefix)
+		}
+		return qualified
+	case []interface{}:
+		qualified := make([]interface{}, 0, len(v))
+		for _, iv := range v {
+			qualified = append(qualified, qualifyInstanceReferences(iv, configPrefix, instancePrefix))
+		}
+		return qualified
+	default:
+		return v
+	}
+}
+
+// isReference returns true if the value is a reference that still needs to be resolved
+func isReference(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && strings.HasPrefix(s, refMarker+".")
+}
+
+// qualifyReference returns the reference ref made from inside the module with address prefix
+// as a reference from the root module
+func qualifyReference(prefix, ref string) string {
+	if prefix == "" {
+		return ref
+	}
+	return fmt.Sprintf("%s.%s", prefix, ref)
+}
@@ -2,11 +2,14 @@ package terraform
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/kaytu-io/pennywise/pkg/diagnostic"
+	"github.com/kaytu-io/pennywise/pkg/schema"
 	"github.com/kaytu-io/pennywise/pkg/usage"
 	"io"
-	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -16,10 +19,12 @@ type Plan struct {
 	providerInitializers map[string]ProviderInitializer
 	usage                usage.Usage
 
-	Configuration Configuration       `json:"configuration"`
-	PriorState    *State              `json:"prior_state"`
-	PlannedValues Values              `json:"planned_values"`
-	Variables     map[string]Variable `json:"variables"`
+	TerraformVersion string              `json:"terraform_version"`
+	Configuration    Configuration       `json:"configuration"`
+	PriorState       *State              `json:"prior_state"`
+	PlannedValues    Values              `json:"planned_values"`
+	ResourceChanges  []ResourceChange    `json:"resource_changes"`
+	Variables        map[string]Variable `json:"variables"`
 }
 
 // SetUsage will set the usage of the plan
@@ -37,6 +42,19 @@ func NewPlan(providerInitializers ...ProviderInitializer) *Plan {
 	return plan
 }
 
+// Tool returns the tool and version that generated the plan. OpenTofu plans have the same format as
+// the Terraform ones so they are identified by the registry their providers come from.
+func (p *Plan) Tool() schema.IaCTool {
+	tool := schema.IaCTool{Name: schema.TerraformTool, Version: p.TerraformVersion}
+	for _, cfg := range p.Configuration.ProviderConfig {
+		if cfg.FullName != "" && ParseProviderSource(cfg.FullName).Hostname == OpenTofuRegistryHostname {
+			tool.Name = schema.OpenTofuTool
+			break
+		}
+	}
+	return tool
+}
+
 // Read reads the Plan file from the provider io.Reader.
 func (p *Plan) Read(r io.Reader) error {
 	if err := json.NewDecoder(r).Decode(p); err != nil {
@@ -80,7 +98,7 @@ func (p *Plan) ExtractPriorQueries() ([]Resource, error) {
 func (p *Plan) extractProviders() (map[string]Provider, error) {
 	providers := make(map[string]Provider)
 	for name, provConfig := range p.Configuration.ProviderConfig {
-		if pi, ok := p.providerInitializers[provConfig.Name]; ok {
+		if pi, ok := p.providerInitializer(provConfig); ok {
 			values, err := p.evaluateProviderConfigExpressions(provConfig)
 			if err != nil {
 				return nil, fmt.Errorf("failed to read config of provider %q: %w", name, err)
@@ -100,29 +118,77 @@ func (p *Plan) extractProviders() (map[string]Provider, error) {
 	return providers, nil
 }
 
+// providerInitializer returns the ProviderInitializer matching the provider config by its name, its fully
+// qualified name or, for the official providers served by other registries (OpenTofu registry, mirrors), its type.
+func (p *Plan) providerInitializer(cfg ProviderConfig) (ProviderInitializer, bool) {
+	if pi, ok := p.providerInitializers[cfg.Name]; ok {
+		return pi, true
+	}
+	if cfg.FullName == "" {
+		return ProviderInitializer{}, false
+	}
+	if pi, ok := p.providerInitializers[cfg.FullName]; ok {
+		return pi, true
+	}
+	source := ParseProviderSource(cfg.FullName)
+	if source.Namespace != "hashicorp" && source.Namespace != "opentofu" {
+		return ProviderInitializer{}, false
+	}
+	pi, ok := p.providerInitializers[source.Type]
+	return pi, ok
+}
+
 // extractResources iterates over every resource and passes each to the corresponding Provider to get the components.
 // These are used to form a slice of resource queries that are then returned back to the caller.
 func (p *Plan) extractResources(values Values, providers map[string]Provider) ([]Resource, error) {
 	// Create a map to associate each resource with a Provider that
 	// should be used to estimate it.
 	resourceProviders := make(map[string]providerWithResourceValues)
-	err := p.extractModuleConfiguration("", &p.Configuration.RootModule, providers, resourceProviders)
+	graph := newReferenceGraph()
+	err := p.extractModuleConfiguration("", &p.Configuration.RootModule, providers, resourceProviders, graph)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract module (%s) configuraiotn: %w", "root_module", err)
 	}
 
 	resourcesMap := p.extractModuleResources(&values.RootModule, resourceProviders)
 
+	graph.addPlannedResources(resourcesMap)
+	if p.PriorState != nil {
+		graph.addPriorModule(&p.PriorState.Values.RootModule)
+	}
+
+	// The ids are only known after apply, every resource gets its address as id so the
+	// references to them resolve whatever order the resources are resolved in
+	for _, rss := range resourcesMap {
+		for _, rs := range rss {
+			rs.Values["id"] = fmt.Sprintf("%s.id", rs.Address)
+		}
+	}
+
 	var retry int
-	var oldResourcesMap map[string][]Resource
 	for retry < 50 {
-		resourcesMap = p.extractReferences(resourcesMap)
-		if mapsEqual(oldResourcesMap, resourcesMap) {
+		if !p.extractReferences(resourcesMap, graph) {
 			break
 		}
-		oldResourcesMap = resourcesMap
 		retry++
 	}
+	// Whatever is left could not be resolved (ex: unknown until apply) so we keep
+	// the raw reference as the value and report it on the resource
+	afterUnknown := make(map[string]interface{})
+	for _, rc := range p.ResourceChanges {
+		afterUnknown[rc.Address] = rc.Change.AfterUnknown
+	}
+	for _, rss := range resourcesMap {
+		for i, rs := range rss {
+			unknown, _ := afterUnknown[rs.Address].(map[string]interface{})
+			for key, val := range rs.Values {
+				rs.Values[key] = unresolvedReferences(val, key, unknown[key], graph, func(d diagnostic.Diagnostic) {
+					rss[i].Diagnostics = append(rss[i].Diagnostics, d)
+				})
+			}
+			sortDiagnostics(rss[i].Diagnostics)
+		}
+	}
 
 	var resources []Resource
 	for _, rss := range resourcesMap {
@@ -132,52 +198,191 @@ func (p *Plan) extractResources(values Values, providers map[string]Provider) ([
 }
 
 type providerWithResourceValues struct {
-	Provider Provider
-	Values   map[string]interface{}
+	Provider          Provider
+	ProviderConfigKey string
+	Values            map[string]interface{}
+	Diagnostics       []diagnostic.Diagnostic
 }
 
-func (p *Plan) extractReferences(resourcesMap map[string][]Resource) map[string][]Resource {
+// extractReferences replaces the references on the resources values with the values they point to.
+// It returns true if any value was replaced so it can be called again to resolve chained references.
+func (p *Plan) extractReferences(resourcesMap map[string][]Resource, graph *referenceGraph) bool {
+	var changed bool
 	for _, resources := range resourcesMap {
 		for i, res := range resources {
-			res.Values["id"] = fmt.Sprintf("%s.id", res.Address)
 			for key, val := range res.Values {
 				if value, ok := val.(string); ok {
 					ref := strings.Split(value, ".")
-					if ref[0] == "*ref*" && len(ref) > 2 {
-						if _, ok := resourcesMap[strings.Join(ref[1:len(ref)-1], ".")]; ok {
-							if refValue, ok := resourcesMap[strings.Join(ref[1:len(ref)-1], ".")][0].Values[ref[len(ref)-1]]; ok {
-								res.Values[key] = refValue
-							}
-						} else {
-							res.Values[key] = nil
-						}
-					} else if ref[0] == "*each*" && len(ref) > 2 {
+					if ref[0] == "*each*" && len(ref) > 2 {
 						if _, ok := resourcesMap[strings.Join(ref[1:len(ref)-1], ".")]; ok && len(resourcesMap[strings.Join(ref[1:len(ref)-1], ".")]) > i {
 							if refValue, ok := resourcesMap[strings.Join(ref[1:len(ref)-1], ".")][i].Values[ref[len(ref)-1]]; ok {
 								res.Values[key] = refValue
+								changed = true
 							}
 						} else {
 							res.Values[key] = nil
+							resources[i].Diagnostics = append(resources[i].Diagnostics, diagnostic.Diagnostic{
+								Kind:      diagnostic.UnresolvedReference,
+								Attribute: key,
+								Message:   fmt.Sprintf("each value of %s could not be resolved", strings.Join(ref[1:len(ref)-1], ".")),
+							})
+							changed = true
 						}
+						continue
 					}
-				} else {
-					continue
+				}
+				if v, ok := resolveReferences(val, graph); ok {
+					res.Values[key] = v
+					changed = true
 				}
 			}
 		}
 	}
-	return resourcesMap
+	return changed
+}
+
+// resolveReferences walks the value and replaces every reference that can be resolved through the graph,
+// the second value is true if anything was replaced
+func resolveReferences(value interface{}, graph *referenceGraph) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		if !isReference(v) {
+			return v, false
+		}
+		rv, ok := graph.resolve(strings.TrimPrefix(v, refMarker+"."))
+		if !ok {
+			return v, false
+		}
+		return rv, true
+	case map[string]interface{}:
+		var changed bool
+		for k, iv := range v {
+			if rv, ok := resolveReferences(iv, graph); ok {
+				v[k] = rv
+				changed = true
+			}
+		}
+		return v, changed
+	case []interface{}:
+		var changed bool
+		for i, iv := range v {
+			if rv, ok := resolveReferences(iv, graph); ok {
+				v[i] = rv
+				changed = true
+			}
+		}
+		return v, changed
+	default:
+		return v, false
+	}
+}
+
+// unresolvedReferences walks the value and replaces every reference left with the raw reference, a diagnostic
+// is reported for each of them. The references to attributes that are unknown on the plan (afterUnknown is the
+// after_unknown of the resource change) are reported as known after apply, the references matching several
+// instances of the graph with different values as ambiguous.
+func unresolvedReferences(value interface{}, attribute string, afterUnknown interface{}, graph *referenceGraph, report func(diagnostic.Diagnostic)) interface{} {
+	switch v := value.(type) {
+	case string:
+		if !isReference(v) {
+			return v
+		}
+		reference := strings.TrimPrefix(v, refMarker+".")
+		if instances, ok := graph.ambiguousInstances(reference); ok {
+			report(diagnostic.Diagnostic{
+				Kind:      diagnostic.UnresolvedReference,
+				Attribute: attribute,
+				Message:   fmt.Sprintf("reference to %s could not be resolved, it matches the instances %s", reference, strings.Join(instances, ", ")),
+			})
+		} else if isUnknown(afterUnknown) {
+			report(diagnostic.Diagnostic{
+				Kind:      diagnostic.UnknownAfterApply,
+				Attribute: attribute,
+				Message:   fmt.Sprintf("value of %s is known after apply", reference),
+			})
+		} else {
+			report(diagnostic.Diagnostic{
+				Kind:      diagnostic.UnresolvedReference,
+				Attribute: attribute,
+				Message:   fmt.Sprintf("reference to %s could not be resolved", reference),
+			})
+		}
+		return reference
+	case map[string]interface{}:
+		unknown, _ := afterUnknown.(map[string]interface{})
+		for k, iv := range v {
+			var au interface{} = afterUnknown
+			if unknown != nil {
+				au = unknown[k]
+			}
+			v[k] = unresolvedReferences(iv, fmt.Sprintf("%s.%s", attribute, k), au, graph, report)
+		}
+		return v
+	case []interface{}:
+		unknown, _ := afterUnknown.([]interface{})
+		for i, iv := range v {
+			var au interface{} = afterUnknown
+			if unknown != nil {
+				au = nil
+				if i < len(unknown) {
+					au = unknown[i]
+				}
+			}
+			v[i] = unresolvedReferences(iv, fmt.Sprintf("%s.%d", attribute, i), au, graph, report)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// isUnknown returns true if the after_unknown value of an attribute marks it, or any of its nested
+// attributes, as unknown until apply
+func isUnknown(afterUnknown interface{}) bool {
+	switch v := afterUnknown.(type) {
+	case bool:
+		return v
+	case map[string]interface{}:
+		for _, iv := range v {
+			if isUnknown(iv) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, iv := range v {
+			if isUnknown(iv) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortDiagnostics sorts the diagnostics by attribute so they are shown in the same order on every run
+func sortDiagnostics(diagnostics []diagnostic.Diagnostic) {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Attribute < diagnostics[j].Attribute
+	})
 }
 
 // extractModuleConfiguration iterates over all the modules included in the plan's configuration block and
 // extracts the provider that should be used for each resource. This function calls itself recursively until
 // data from the entire module tree is extracted. It takes the following arguments:
-//   - prefix - the current module's address. Empty string signifies the root module.
+//   - prefix - the current module's address (ex: module.a.module.b). Empty string signifies the root module.
 //   - module - the module's configuration block itself.
 //   - providers - map of provider name to Provider.
 //   - resourceProviders - used as an output of this function, it's a map of resource addresses to their assigned
 //     Provider and the values on the resource. This map should be passed empty and not nil.
-func (p *Plan) extractModuleConfiguration(prefix string, module *ConfigurationModule, providers map[string]Provider, resourceProviders map[string]providerWithResourceValues) error {
+//   - graph - used as an output of this function, the module outputs and the inputs of the child modules are
+//     added to it so references to them can be resolved.
+func (p *Plan) extractModuleConfiguration(prefix string, module *ConfigurationModule, providers map[string]Provider, resourceProviders map[string]providerWithResourceValues, graph *referenceGraph) error {
+	// Only the root module variables have values on the plan, the child modules
+	// ones are resolved through the inputs of the module call
+	variables := module.Variables
+	if prefix == "" {
+		variables = p.Variables
+	}
+
 	for _, res := range module.Resources {
 		key := res.ProviderConfigKey
 		if strings.Contains(key, ":") {
@@ -185,31 +390,62 @@ func (p *Plan) extractModuleConfiguration(prefix string, module *ConfigurationMo
 			key = parts[len(parts)-1]
 		}
 
-		addr := res.Address
-		if prefix != "" {
-			addr = fmt.Sprintf("module.%s.%s", prefix, addr)
-		}
+		addr := qualifyReference(prefix, res.Address)
 
 		if prov, ok := providers[key]; ok {
-			resPrefix := fmt.Sprintf("module.%s", prefix)
-			rv, err := p.evaluateResourceExpressions(resPrefix, res.ForEachExpression, res.Expressions, module.Variables)
+			rv, diagnostics, err := p.evaluateResourceExpressions(prefix, res.ForEachExpression, res.Expressions, variables)
 			if err != nil {
 				return fmt.Errorf("failed to evaluate resource expresions: %w", err)
 			}
 			resourceProviders[addr] = providerWithResourceValues{
-				Provider: prov,
-				Values:   rv,
+				Provider:          prov,
+				ProviderConfigKey: key,
+				Values:            rv,
+				Diagnostics:       diagnostics,
+			}
+		}
+	}
+
+	if prefix != "" {
+		for name, output := range module.Outputs {
+			v, ok, err := p.evaluateExpression(prefix, nil, output.Expression, variables)
+			if unsupportedErr := new(UnsupportedExpressionError); errors.As(err, &unsupportedErr) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to evaluate output %q: %w", name, err)
+			}
+			if ok {
+				graph.setExpression(qualifyReference(prefix, name), v)
 			}
 		}
 	}
 
 	for k, child := range module.ModuleCalls {
 		if child.Module != nil {
-			nextPrefix := k
-			if prefix != "" {
-				nextPrefix = fmt.Sprintf("%s.%s", prefix, k)
+			nextPrefix := qualifyReference(prefix, fmt.Sprintf("module.%s", k))
+			for name, v := range child.Module.Variables {
+				if v.Default != nil {
+					graph.setExpression(fmt.Sprintf("%s.var.%s", nextPrefix, name), v.Default)
+				}
+			}
+			for name, ex := range child.Expressions {
+				m, ok := ex.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				v, ok, err := p.evaluateExpression(prefix, nil, m, variables)
+				if unsupportedErr := new(UnsupportedExpressionError); errors.As(err, &unsupportedErr) {
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("failed to evaluate input %q of module %s: %w", name, nextPrefix, err)
+				}
+				if ok {
+					graph.setExpression(fmt.Sprintf("%s.var.%s", nextPrefix, name), v)
+				}
 			}
-			err := p.extractModuleConfiguration(nextPrefix, child.Module, providers, resourceProviders)
+			err := p.extractModuleConfiguration(nextPrefix, child.Module, providers, resourceProviders, graph)
 			if err != nil {
 				return fmt.Errorf("failed to extract child (%s) module configuration: %w", nextPrefix, err)
 			}
@@ -224,27 +460,16 @@ func (p *Plan) extractModuleResources(module *Module, resourceProviders map[stri
 	rss := make(map[string]Resource)
 	resources := make(map[string][]Resource)
 	for _, tfres := range module.Resources {
-		arrayRegex := regexp.MustCompile(`^([^[]+)(?:\[(\d+)\])?$`)
-		arrayMatch := arrayRegex.FindStringSubmatch(tfres.Address)
-		var pwrv providerWithResourceValues
-		if arrayMatch != nil {
-			if arrayMatch[2] != "" {
-				pwrvTemp, ok := resourceProviders[arrayMatch[1]]
-				if ok {
-					pwrv = pwrvTemp
-				}
-			}
-		}
-		pwrvTemp, ok := resourceProviders[fmt.Sprintf("%s.%s", tfres.Type, tfres.Name)]
+		// The configuration is shared by the instances of the resource and of its modules
+		pwrv, ok := resourceProviders[tfres.Address]
 		if !ok {
-			pwrvTemp, ok = resourceProviders[tfres.Address]
-			if ok {
-				pwrv = pwrvTemp
-			}
-		} else {
-			pwrv = pwrvTemp
+			pwrv = resourceProviders[stripInstanceKeys(tfres.Address)]
+		}
+		values := pwrv.Values
+		if prefix := modulePrefix(tfres.Address); prefix != stripInstanceKeys(prefix) {
+			values = qualifyInstanceReferences(values, stripInstanceKeys(prefix), prefix).(map[string]interface{})
 		}
-		for k, v := range pwrv.Values {
+		for k, v := range values {
 			if v == nil {
 				continue
 			}
@@ -294,6 +519,8 @@ func (p *Plan) extractModuleResources(module *Module, resourceProviders map[stri
 				continue
 			}
 		}
+		tfres.ProviderConfigKey = pwrv.ProviderConfigKey
+		tfres.Diagnostics = append([]diagnostic.Diagnostic{}, pwrv.Diagnostics...)
 		rss[tfres.Address] = tfres
 		tfres.Values[usage.Key] = p.usage.GetUsage(tfres.Type, tfres.Address)
 	}
@@ -352,9 +579,12 @@ func (p *Plan) evaluateProviderConfigExpressions(config ProviderConfig) (map[str
 }
 
 // evaluateResourceExpressions returns evaluated values of resource's configuration block, whether a constant
-// value or reference to a variable.
-func (p *Plan) evaluateResourceExpressions(prefix string, forEach map[string]interface{}, config map[string]interface{}, variables map[string]Variable) (map[string]interface{}, error) {
+// value or reference to a variable. References to other resources, data sources and modules are returned
+// with the refMarker prefix and qualified with the module prefix so they can be resolved later on.
+// The expressions that can't be evaluated (ex: missing variables) are skipped and returned as diagnostics.
+func (p *Plan) evaluateResourceExpressions(prefix string, forEach map[string]interface{}, config map[string]interface{}, variables map[string]Variable) (map[string]interface{}, []diagnostic.Diagnostic, error) {
 	values := make(map[string]interface{})
+	var diagnostics []diagnostic.Diagnostic
 	for name, ex := range config {
 		m, ok := ex.(map[string]interface{})
 		if !ok {
@@ -372,150 +602,132 @@ func (p *Plan) evaluateResourceExpressions(prefix string, forEach map[string]int
 					// that can be defined multiple times so it should always be map[]
 					continue
 				}
-				av, err := p.evaluateResourceExpressions(prefix, forEach, mc, variables)
+				av, ad, err := p.evaluateResourceExpressions(prefix, forEach, mc, variables)
 				if err != nil {
-					return nil, fmt.Errorf("failed to evaluateResourceExpressions on array: %w", err)
+					return nil, nil, fmt.Errorf("failed to evaluateResourceExpressions on array: %w", err)
 				}
+				diagnostics = append(diagnostics, diagnostic.WithPrefix(fmt.Sprintf("%s.%d", name, len(values[name].([]interface{}))), ad)...)
 				values[name] = append(values[name].([]interface{}), av)
 			}
-		}
-		refs, ok := m["references"].([]interface{})
-		if !ok {
-			refs = make([]interface{}, 0, 0)
-		}
-
-		if len(m) > 0 && m["constant_value"] == nil && len(refs) == 0 {
-			// Right now the only key identified empty has been `timeout`
 			continue
 		}
-		if m["constant_value"] != nil {
-			values[name] = m["constant_value"]
+		value, ok, err := p.evaluateExpression(prefix, forEach, m, variables)
+		if d, isDiagnostic := asDiagnostic(name, err); isDiagnostic {
+			diagnostics = append(diagnostics, d)
 			continue
 		}
-
-		if len(refs) < 1 {
-			continue
+		if err != nil {
+			return nil, nil, err
 		}
+		if ok {
+			values[name] = value
+		}
+	}
+	return values, diagnostics, nil
+}
 
-		ref := strings.Split(refs[0].(string), ".")
-		if len(ref) < 2 {
-			return nil, fmt.Errorf("refernce %q has invalid format", refs[0])
+// evaluateExpression returns the evaluated value of a single expression, the second value is false
+// if the expression has to be ignored.
+func (p *Plan) evaluateExpression(prefix string, forEach map[string]interface{}, m map[string]interface{}, variables map[string]Variable) (interface{}, bool, error) {
+	refs, ok := m["references"].([]interface{})
+	if !ok {
+		refs = make([]interface{}, 0, 0)
+	}
+
+	if len(m) > 0 && m["constant_value"] == nil && len(refs) == 0 {
+		// Right now the only key identified empty has been `timeout`
+		return nil, false, nil
+	}
+	if m["constant_value"] != nil {
+		return m["constant_value"], true, nil
+	}
+
+	if len(refs) < 1 {
+		return nil, false, nil
+	}
+
+	ref := strings.Split(refs[0].(string), ".")
+	if len(ref) < 2 {
+		return nil, false, fmt.Errorf("refernce %q has invalid format", refs[0])
+	}
+	if ref[0] == "each" {
+		if forEach == nil || forEach["references"] == nil {
+			return nil, false, &UnsupportedExpressionError{Reason: fmt.Sprintf("%s is only supported when for_each is a reference", refs[0])}
 		}
-		if ref[0] == "each" {
-			if ref[1] == "key" {
-				values[name] = fmt.Sprintf("*each*.%s.key", forEach["references"].([]interface{})[0])
-				continue
-			} else if ref[1] == "value" && len(ref) == 2 {
-				values[name] = fmt.Sprintf("*each*.%s", forEach["references"].([]interface{})[0])
-				continue
-			} else if strings.Contains(ref[1], "value") && len(ref) == 2 {
-				regex := regexp.MustCompile(`^value\["([^"]+)"\]$`)
-				match := regex.FindStringSubmatch(ref[1])
-				if match != nil {
-					values[name] = fmt.Sprintf("*each*.%s[%s]", forEach["references"].([]interface{})[0], match[1])
-					continue
-				}
-			}
-			if forEach["references"] != nil {
-				values[name] = fmt.Sprintf("*each*.%s.%s", forEach["references"].([]interface{})[0], ref[2])
+		if ref[1] == "key" {
+			return fmt.Sprintf("*each*.%s.key", forEach["references"].([]interface{})[0]), true, nil
+		} else if ref[1] == "value" && len(ref) == 2 {
+			return fmt.Sprintf("*each*.%s", forEach["references"].([]interface{})[0]), true, nil
+		} else if strings.Contains(ref[1], "value") && len(ref) == 2 {
+			regex := regexp.MustCompile(`^value\["([^"]+)"\]$`)
+			match := regex.FindStringSubmatch(ref[1])
+			if match != nil {
+				return fmt.Sprintf("*each*.%s[%s]", forEach["references"].([]interface{})[0], match[1]), true, nil
 			}
-			continue
 		}
-		// "local" variables are not set on the plan
-		// so we ignore them
-		if ref[0] == "local" {
-			continue
+		if len(ref) < 3 {
+			return nil, false, &UnsupportedExpressionError{Reason: fmt.Sprintf("%s is not supported", refs[0])}
 		}
+		return fmt.Sprintf("*each*.%s.%s", forEach["references"].([]interface{})[0], ref[2]), true, nil
+	}
+	// "local" variables are not set on the plan
+	// so we ignore them
+	if ref[0] == "local" {
+		return nil, false, &UnsupportedExpressionError{Reason: fmt.Sprintf("local values are not set on the plan (%s)", refs[0])}
+	}
 
-		// For now we do not want external module references or data references
-		if ref[0] == "module" || ref[0] == "data" {
-			continue
+	if ref[0] == "var" {
+		// Variables of child modules are set by the module call
+		// so they are resolved like any other reference
+		if prefix != "" {
+			return fmt.Sprintf("%s.%s", refMarker, qualifyReference(prefix, refs[0].(string))), true, nil
 		}
 
-		if ref[0] == "var" {
-			varName := ref[1]
-
-			arrayRegex := regexp.MustCompile(`^([^[]+)(?:\[(\d+)\])?$`)
-			arrayMatch := arrayRegex.FindStringSubmatch(varName)
-			if arrayMatch != nil {
-				if arrayMatch[2] != "" {
-					v, ok := variables[arrayMatch[1]]
-					if !ok || v.Value == "" {
-						return nil, fmt.Errorf("required variable %q is not defined", varName)
-					}
-					if value, ok := v.Value.([]interface{}); ok {
-						index, err := strconv.Atoi(arrayMatch[2])
-						if err != nil {
-							values[name] = nil
-						} else {
-							values[name] = value[index]
-						}
-					} else {
-						values[name] = v.Value
-					}
-					continue
-				}
-			}
+		varName := ref[1]
 
-			mapRegex := regexp.MustCompile(`^([^[]+)\[\"([^\"]+)\"\]$`)
-			mapMatch := mapRegex.FindStringSubmatch(varName)
-			if mapMatch != nil {
-				v, ok := variables[mapMatch[1]]
+		arrayRegex := regexp.MustCompile(`^([^[]+)(?:\[(\d+)\])?$`)
+		arrayMatch := arrayRegex.FindStringSubmatch(varName)
+		if arrayMatch != nil {
+			if arrayMatch[2] != "" {
+				v, ok := variables[arrayMatch[1]]
 				if !ok || v.Value == "" {
-					return nil, fmt.Errorf("required variable %q is not defined", varName)
+					return nil, false, &VariableNotDefinedError{Name: varName}
 				}
-				if valueMap, ok := v.Value.(map[string]interface{}); ok {
-					if value, ok := valueMap[mapMatch[2]]; ok {
-						values[name] = value
-					} else {
-						values[name] = nil
+				if value, ok := v.Value.([]interface{}); ok {
+					index, err := strconv.Atoi(arrayMatch[2])
+					if err != nil || index >= len(value) {
+						return nil, true, nil
 					}
-				} else {
-					values[name] = v.Value
+					return value[index], true, nil
 				}
-				continue
-			}
-
-			v, ok := variables[varName]
-			if !ok || v.Value == "" {
-				return nil, fmt.Errorf("required variable %q is not defined", varName)
+				return v.Value, true, nil
 			}
-			values[name] = v.Value
-			continue
 		}
-		values[name] = fmt.Sprintf("%s", refs[0])
-	}
-	return values, nil
-}
 
-// mapsEqual checks if two maps are equal
-func mapsEqual(resourcesMap1, resourcesMap2 map[string][]Resource) bool {
-	if len(resourcesMap1) != len(resourcesMap2) {
-		return false
-	}
-
-	for key, value1 := range resourcesMap1 {
-		if value2, ok := resourcesMap2[key]; ok {
-			if len(value1) != len(value2) {
-				return false
+		mapRegex := regexp.MustCompile(`^([^[]+)\[\"([^\"]+)\"\]$`)
+		mapMatch := mapRegex.FindStringSubmatch(varName)
+		if mapMatch != nil {
+			v, ok := variables[mapMatch[1]]
+			if !ok || v.Value == "" {
+				return nil, false, &VariableNotDefinedError{Name: varName}
 			}
-			for i, _ := range value1 {
-				map1 := value1[i].Values
-				map2 := value2[i].Values
-				for k, v1 := range map1 {
-					if v2, ok := map2[k]; ok {
-						if !reflect.DeepEqual(v1, v2) {
-							return false
-						}
-					} else {
-						return false
-					}
+			if valueMap, ok := v.Value.(map[string]interface{}); ok {
+				if value, ok := valueMap[mapMatch[2]]; ok {
+					return value, true, nil
 				}
+				return nil, true, nil
 			}
-		} else {
-			return false
+			return v.Value, true, nil
+		}
+
+		v, ok := variables[varName]
+		if !ok || v.Value == "" {
+			return nil, false, &VariableNotDefinedError{Name: varName}
 		}
+		return v.Value, true, nil
 	}
 
-	return true
+	// Everything else is a reference to a resource, a data source or a module output
+	// which is resolved once all the resources are known
+	return fmt.Sprintf("%s.%s", refMarker, qualifyReference(prefix, refs[0].(string))), true, nil
 }
@@ -1,5 +1,10 @@
 package terraform
 
+import (
+	"fmt"
+	"strings"
+)
+
 //go:generate mockgen -destination=../mock/terraform_provider.go -mock_names=Provider=TerraformProvider -package mock github.com/cycloidio/terracost/terraform Provider
 
 // Provider represents a Terraform provider. It extracts price queries from Terraform resources.
@@ -19,3 +24,43 @@ type ProviderInitializer struct {
 	// If a provider must be ignored (related to version constraints, etc), please return nil to avoid using it.
 	Provider func(values map[string]interface{}) (Provider, error)
 }
+
+const (
+	// TerraformRegistryHostname is the hostname of the default Terraform registry
+	TerraformRegistryHostname = "registry.terraform.io"
+	// OpenTofuRegistryHostname is the hostname of the default OpenTofu registry
+	OpenTofuRegistryHostname = "registry.opentofu.org"
+)
+
+// ProviderSource is the source address of a provider in the form of [hostname/]namespace/type,
+// ex: registry.terraform.io/hashicorp/aws.
+type ProviderSource struct {
+	Hostname  string
+	Namespace string
+	Type      string
+}
+
+// ParseProviderSource parses the source address of a provider, the hostname and namespace are
+// optional and default to the Terraform registry and `hashicorp` as Terraform does.
+func ParseProviderSource(source string) ProviderSource {
+	ps := ProviderSource{
+		Hostname:  TerraformRegistryHostname,
+		Namespace: "hashicorp",
+	}
+	parts := strings.Split(strings.ToLower(source), "/")
+	switch len(parts) {
+	case 1:
+		ps.Type = parts[0]
+	case 2:
+		ps.Namespace, ps.Type = parts[0], parts[1]
+	default:
+		ps.Hostname = strings.Join(parts[:len(parts)-2], "/")
+		ps.Namespace, ps.Type = parts[len(parts)-2], parts[len(parts)-1]
+	}
+	return ps
+}
+
+// String returns the fully qualified source address
+func (ps ProviderSource) String() string {
+	return fmt.Sprintf("%s/%s/%s", ps.Hostname, ps.Namespace, ps.Type)
+}
@@ -2,19 +2,20 @@ package terraform
 
 import (
 	"encoding/json"
+	"github.com/kaytu-io/pennywise/pkg/diagnostic"
 	"github.com/kaytu-io/pennywise/pkg/schema"
-	"strings"
 )
 
 // ProviderConfigExpression is a single configuration variable of a ProviderConfig.
 type ProviderConfigExpression struct {
-	ConstantValue interface{} `json:"constant_value",mapstructure:"constant_value"`
-	References    []string    `json:"references",mapstructure:"references"`
+	ConstantValue interface{} `json:"constant_value" mapstructure:"constant_value"`
+	References    []string    `json:"references" mapstructure:"references"`
 }
 
 // ProviderConfig is configuration of a provider with the given Name.
 type ProviderConfig struct {
 	Name        string                              `json:"name"`
+	FullName    string                              `json:"full_name"`
 	Alias       string                              `json:"alias"`
 	Expressions map[string]ProviderConfigExpression `json:"expressions"`
 }
@@ -25,6 +26,7 @@ type ProviderConfig struct {
 func (cfg *ProviderConfig) UnmarshalJSON(b []byte) error {
 	var s struct {
 		Name        string                 `json:"name"`
+		FullName    string                 `json:"full_name"`
 		Alias       string                 `json:"alias"`
 		Expressions map[string]interface{} `json:"expressions"`
 	}
@@ -33,6 +35,7 @@ func (cfg *ProviderConfig) UnmarshalJSON(b []byte) error {
 	}
 
 	cfg.Name = s.Name
+	cfg.FullName = s.FullName
 	cfg.Alias = s.Alias
 	cfg.Expressions = make(map[string]ProviderConfigExpression)
 
@@ -84,22 +87,26 @@ type Resource struct {
 	Name         string                 `json:"name"`
 	ProviderName string                 `json:"provider_name"`
 	Values       map[string]interface{} `json:"values"`
+
+	// ProviderConfigKey is the key of the provider configuration of the resource, with its alias if
+	// any (ex: aws.west), it's empty if the resource is not in the configuration of the plan
+	ProviderConfigKey string `json:"-"`
+	// Diagnostics are the issues found while evaluating the resource values
+	Diagnostics []diagnostic.Diagnostic `json:"-"`
 }
 
+// ToResource returns the resource definition in the region, its provider is the type of its provider (ex: google-beta)
+// that is resolved with the registry of the providers.
 func (r *Resource) ToResource(region string) schema.ResourceDef {
-	resourceDef := schema.ResourceDef{
-		Address:    r.Address,
-		Type:       r.Type,
-		Name:       r.Name,
-		RegionCode: region,
-		Values:     r.Values,
-	}
-	if strings.Contains(r.ProviderName, "azurerm") {
-		resourceDef.ProviderName = schema.AzureProvider
-	} else {
-		resourceDef.ProviderName = schema.AWSProvider
+	return schema.ResourceDef{
+		Address:      r.Address,
+		Type:         r.Type,
+		Name:         r.Name,
+		RegionCode:   region,
+		ProviderName: schema.ProviderName(ParseProviderSource(r.ProviderName).Type),
+		Values:       r.Values,
+		Diagnostics:  r.Diagnostics,
 	}
-	return resourceDef
 }
 
 // Module is a collection of resources.
@@ -117,16 +124,28 @@ type Configuration struct {
 
 // Variable is a Terraform variable declaration.
 type Variable struct {
-	Value interface{} `json:"value"`
+	Value   interface{} `json:"value"`
+	Default interface{} `json:"default"`
 }
 
 // ConfigurationModule is used to configure a module.
 type ConfigurationModule struct {
-	Resources   []ConfigurationResource `json:"resources"`
-	Variables   map[string]Variable     `json:"variables"`
-	ModuleCalls map[string]struct {
-		Module *ConfigurationModule `json:"module"`
-	} `json:"module_calls"`
+	Resources   []ConfigurationResource        `json:"resources"`
+	Variables   map[string]Variable            `json:"variables"`
+	Outputs     map[string]ConfigurationOutput `json:"outputs"`
+	ModuleCalls map[string]ModuleCall          `json:"module_calls"`
+}
+
+// ModuleCall is the call of a child module with the expressions of its inputs.
+type ModuleCall struct {
+	Source      string                 `json:"source"`
+	Expressions map[string]interface{} `json:"expressions"`
+	Module      *ConfigurationModule   `json:"module"`
+}
+
+// ConfigurationOutput is an output declared on a module.
+type ConfigurationOutput struct {
+	Expression map[string]interface{} `json:"expression"`
 }
 
 // ConfigurationResource is used to configure a single reosurce.
@@ -139,3 +158,13 @@ type ConfigurationResource struct {
 	Expressions       map[string]interface{} `json:"expressions"`
 	ForEachExpression map[string]interface{} `json:"for_each_expression,omitempty"`
 }
+
+// ResourceChange is the change planned on a resource instance
+type ResourceChange struct {
+	Address string `json:"address"`
+	Change  struct {
+		// AfterUnknown has the same structure as the values of the resource, with true on the
+		// attributes whose value will only be known after apply
+		AfterUnknown interface{} `json:"after_unknown"`
+	} `json:"change"`
+}
@@ -0,0 +1,53 @@
+package plan
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Binaries are the names of the binaries looked up on the PATH to generate plans, in order of preference
+var Binaries = []string{"terraform", "tofu"}
+
+// ErrBinaryNotFound is returned when none of the Binaries can be found
+var ErrBinaryNotFound = errors.New("neither terraform nor tofu binary found in PATH, please install one of them or set the binary path with --terraform-binary")
+
+// FindBinary returns the path of the binary used to generate plans. If binary is set it's used
+// as is (name on the PATH or path to the binary), otherwise the first of the Binaries found is returned.
+func FindBinary(binary string) (string, error) {
+	if binary != "" {
+		path, err := exec.LookPath(binary)
+		if err != nil {
+			return "", fmt.Errorf("terraform binary %q not found: %w", binary, err)
+		}
+		return path, nil
+	}
+	for _, name := range Binaries {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", ErrBinaryNotFound
+}
+
+// run runs the binary with the given arguments in the dir and returns the stdout
+func run(binary, dir string, env []string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "TF_IN_AUTOMATION=true")
+	cmd.Env = append(cmd.Env, env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("%s %s failed: %s", binary, args[0], msg)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,99 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Options are the options used to generate a plan of a project
+type Options struct {
+	// Dir is the directory of the terraform project
+	Dir string
+	// Binary is the terraform or tofu binary to use, if empty it's looked up on the PATH
+	Binary string
+	// VarFiles are the variables files passed to the plan
+	VarFiles []string
+	// Env are additional environment variables set when running the binary
+	Env map[string]string
+	// Workspace is the terraform workspace the plan is generated for
+	Workspace string
+	// NoBackend runs init with -backend=false so no backend credentials are needed
+	NoBackend bool
+}
+
+// lockFile is the dependency lock file init writes in the project directory
+const lockFile = ".terraform.lock.hcl"
+
+// Generate runs init, plan and show on the project and returns the plan in JSON format.
+// The working data (modules, providers, backend state and the plan file) is kept in a
+// temporary directory and the lock file of the project is restored, so the project is left as it was.
+func Generate(opts Options) ([]byte, error) {
+	binary, err := FindBinary(opts.Binary)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "pennywise-plan-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	restoreLockFile, err := preserveFile(filepath.Join(dir, lockFile))
+	if err != nil {
+		return nil, err
+	}
+	defer restoreLockFile()
+
+	env := []string{fmt.Sprintf("TF_DATA_DIR=%s", filepath.Join(tmpDir, ".terraform"))}
+	for k, v := range opts.Env {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	if opts.Workspace != "" {
+		env = append(env, fmt.Sprintf("TF_WORKSPACE=%s", opts.Workspace))
+	}
+
+	initArgs := []string{"init", "-input=false", "-no-color"}
+	if opts.NoBackend {
+		initArgs = append(initArgs, "-backend=false")
+	}
+	if _, err := run(binary, dir, env, initArgs...); err != nil {
+		return nil, err
+	}
+
+	planFile := filepath.Join(tmpDir, "tfplan")
+	planArgs := []string{"plan", "-input=false", "-no-color", "-lock=false", fmt.Sprintf("-out=%s", planFile)}
+	for _, f := range opts.VarFiles {
+		// The var files are relative to where pennywise runs
+		// and not to the project directory
+		path, err := filepath.Abs(f)
+		if err != nil {
+			return nil, err
+		}
+		planArgs = append(planArgs, fmt.Sprintf("-var-file=%s", path))
+	}
+	if _, err := run(binary, dir, env, planArgs...); err != nil {
+		return nil, err
+	}
+
+	return run(binary, dir, env, "show", "-json", "-no-color", planFile)
+}
+
+// preserveFile returns a function restoring the path to its current content,
+// or removing it if it doesn't exist yet
+func preserveFile(path string) (func(), error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return func() { os.Remove(path) }, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return func() { os.WriteFile(path, content, 0644) }, nil
+}
@@ -0,0 +1,56 @@
+package plan
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// binaryMagic are the first bytes of a binary plan file (the -out file), which is a zip archive
+var binaryMagic = []byte("PK\x03\x04")
+
+// IsBinary returns true if the content is a binary plan file and not a plan in JSON format
+func IsBinary(content []byte) bool {
+	return bytes.HasPrefix(content, binaryMagic)
+}
+
+// Read reads the plan file on path and returns it in JSON format. Binary plan files are converted
+// with terraform (or tofu) show, which runs on opts.Dir as it needs the providers of the project initialized.
+func Read(path string, opts Options) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !IsBinary(content) {
+		return content, nil
+	}
+
+	binary, err := FindBinary(opts.Binary)
+	if err != nil {
+		return nil, fmt.Errorf("binary plan file %s needs to be converted to json: %w", path, err)
+	}
+	return Show(binary, opts.Dir, path)
+}
+
+// Show returns the binary plan file on path in JSON format
+func Show(binary, dir, path string) ([]byte, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	return run(binary, dir, nil, "show", "-json", "-no-color", path)
+}
+
+// ShowState returns the state of the project on dir in JSON format, ex: the resources deployed by terraform apply
+func ShowState(binary, dir string) ([]byte, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	return run(binary, dir, nil, "show", "-json", "-no-color")
+}
@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"github.com/kaytu-io/pennywise/pkg/cost"
+	"github.com/kaytu-io/pennywise/pkg/diagnostic"
+)
+
+// AddDiagnostics sets the diagnostics of the submission resources on their costs, the costs returned
+// by the server don't have them
+func (s *Submission) AddDiagnostics(state *cost.State) {
+	diagnostics := resourcesDiagnostics(s.Resources)
+	for address, res := range state.Resources {
+		if d, ok := diagnostics[address]; ok {
+			res.Diagnostics = d
+			state.Resources[address] = res
+		}
+	}
+}
+
+// AddDiagnostics sets the diagnostics of the submission resources on the costs of the modules
+func (s *SubmissionV2) AddDiagnostics(state *cost.ModularState) {
+	addModuleDiagnostics(state, resourcesDiagnostics(s.GetResources()))
+}
+
+func addModuleDiagnostics(state *cost.ModularState, diagnostics map[string][]diagnostic.Diagnostic) {
+	for address, res := range state.Resources {
+		if d, ok := diagnostics[address]; ok {
+			res.Diagnostics = d
+			state.Resources[address] = res
+		}
+	}
+	for name, child := range state.ChildModules {
+		addModuleDiagnostics(&child, diagnostics)
+		state.ChildModules[name] = child
+	}
+}
+
+// AddDiffDiagnostics sets the diagnostics of the submission resources on the costs diff of the submission
+func (s *Submission) AddDiffDiagnostics(state *ModularStateDiff) {
+	addModuleDiffDiagnostics(state, resourcesDiagnostics(s.Resources))
+}
+
+// AddDiffDiagnostics sets the diagnostics of the submission resources on the costs diff of the modules
+func (s *SubmissionV2) AddDiffDiagnostics(state *ModularStateDiff) {
+	addModuleDiffDiagnostics(state, resourcesDiagnostics(s.GetResources()))
+}
+
+func addModuleDiffDiagnostics(state *ModularStateDiff, diagnostics map[string][]diagnostic.Diagnostic) {
+	for address, res := range state.Resources {
+		if d, ok := diagnostics[address]; ok {
+			res.Diagnostics = d
+			state.Resources[address] = res
+		}
+	}
+	for name, child := range state.ChildModules {
+		addModuleDiffDiagnostics(&child, diagnostics)
+		state.ChildModules[name] = child
+	}
+}
+
+func resourcesDiagnostics(resources []ResourceDef) map[string][]diagnostic.Diagnostic {
+	diagnostics := make(map[string][]diagnostic.Diagnostic)
+	for _, res := range resources {
+		if len(res.Diagnostics) > 0 {
+			diagnostics[res.Address] = res.Diagnostics
+		}
+	}
+	return diagnostics
+}
@@ -1,7 +1,10 @@
 package schema
 
 import (
+	"fmt"
+
 	"github.com/kaytu-io/pennywise/pkg/cost"
+	"github.com/kaytu-io/pennywise/pkg/diagnostic"
 	"github.com/shopspring/decimal"
 )
 
@@ -28,6 +31,20 @@ type ModularStateDiff struct {
 	Action    Action
 }
 
+// DiagnosticResourcesCount returns the number of resources of the module and its child modules that have diagnostics
+func (s *ModularStateDiff) DiagnosticResourcesCount() int {
+	var count int
+	for _, res := range s.Resources {
+		if len(res.Diagnostics) > 0 {
+			count++
+		}
+	}
+	for _, child := range s.ChildModules {
+		count += child.DiagnosticResourcesCount()
+	}
+	return count
+}
+
 func (s *ModularStateDiff) TotalResourcesCount() int {
 	return resourcesCount(*s)
 }
@@ -55,6 +72,8 @@ type ResourceDiff struct {
 	PriorCost      decimal.Decimal
 	NewCost        decimal.Decimal
 	Action         Action
+	// Diagnostics are the issues found while parsing the current resource, its cost may be inaccurate if there are any
+	Diagnostics []diagnostic.Diagnostic
 }
 
 // ComponentDiff type to show diff of a Component
@@ -67,3 +86,117 @@ type ComponentDiff struct {
 	Action   Action
 	CostDiff decimal.Decimal
 }
+
+// PeriodCosts returns the prior and new costs for the period of the resources of the state and its child modules,
+// computed from the diffs of their components
+func (s *ModularStateDiff) PeriodCosts(period cost.Period) (prior, new decimal.Decimal) {
+	for _, res := range s.Resources {
+		p, n := res.PeriodCosts(period)
+		prior, new = prior.Add(p), new.Add(n)
+	}
+	for _, child := range s.ChildModules {
+		p, n := child.PeriodCosts(period)
+		prior, new = prior.Add(p), new.Add(n)
+	}
+	return prior, new
+}
+
+// PeriodCosts returns the prior and new costs for the period of the resource, computed from the diffs of its
+// components
+func (r *ResourceDiff) PeriodCosts(period cost.Period) (prior, new decimal.Decimal) {
+	for _, diffs := range r.ComponentDiffs {
+		for _, c := range diffs {
+			p, n := c.PeriodCosts(period)
+			prior, new = prior.Add(p), new.Add(n)
+		}
+	}
+	return prior, new
+}
+
+// PeriodCosts returns the prior and new costs for the period of the component, the created components have no prior
+// cost and the removed components no new cost
+func (c *ComponentDiff) PeriodCosts(period cost.Period) (prior, new decimal.Decimal) {
+	switch c.Action {
+	case ActionCreate:
+		return decimal.Zero, c.Component.PeriodCost(period).Decimal
+	case ActionRemove:
+		return c.Component.PeriodCost(period).Decimal, decimal.Zero
+	}
+	if c.CompareTo != nil {
+		prior = c.CompareTo.PeriodCost(period).Decimal
+	}
+	if c.Current != nil {
+		new = c.Current.PeriodCost(period).Decimal
+	}
+	return prior, new
+}
+
+// Convert converts the costs of the diffs of the state, its resources and its child modules to the currency, the
+// costs of the diffs are in the currency of their component rates or in cost.DefaultCurrency
+func (s *ModularStateDiff) Convert(currency string) error {
+	var err error
+	if s.PriorCost, err = convertDecimal(s.PriorCost, cost.DefaultCurrency, currency); err != nil {
+		return err
+	}
+	if s.NewCost, err = convertDecimal(s.NewCost, cost.DefaultCurrency, currency); err != nil {
+		return err
+	}
+	for name, res := range s.Resources {
+		if err := res.Convert(currency); err != nil {
+			return fmt.Errorf("failed to convert the costs of resource %s: %w", name, err)
+		}
+		s.Resources[name] = res
+	}
+	for name, child := range s.ChildModules {
+		if err := child.Convert(currency); err != nil {
+			return err
+		}
+		s.ChildModules[name] = child
+	}
+	return nil
+}
+
+// Convert converts the costs of the diff of the resource and of its components to the currency
+func (r *ResourceDiff) Convert(currency string) error {
+	var err error
+	if r.PriorCost, err = convertDecimal(r.PriorCost, cost.DefaultCurrency, currency); err != nil {
+		return err
+	}
+	if r.NewCost, err = convertDecimal(r.NewCost, cost.DefaultCurrency, currency); err != nil {
+		return err
+	}
+	for _, diffs := range r.ComponentDiffs {
+		for i := range diffs {
+			if err := diffs[i].Convert(currency); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Convert converts the rates of the components of the diff and its cost diff to the currency
+func (c *ComponentDiff) Convert(currency string) error {
+	var err error
+	if c.CostDiff, err = convertDecimal(c.CostDiff, c.Component.Rate.Currency, currency); err != nil {
+		return err
+	}
+	for _, comp := range []*cost.Component{&c.Component, c.Current, c.CompareTo} {
+		if comp == nil {
+			continue
+		}
+		if comp.Rate, err = comp.Rate.Convert(currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convertDecimal converts the amount of the currency to the other currency
+func convertDecimal(amount decimal.Decimal, from, to string) (decimal.Decimal, error) {
+	c, err := cost.Cost{Decimal: amount, Currency: from}.Convert(to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return c.Decimal, nil
+}
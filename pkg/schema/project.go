@@ -7,19 +7,31 @@ import (
 	"gopkg.in/yaml.v2"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
 
 const (
 	ConfigPath = "pennywise_projects_config.yaml"
+	// DefaultWorkspace is the terraform workspace of the submissions made without a workspace
+	DefaultWorkspace = "default"
 )
 
+// NormalizeWorkspace returns the workspace, or DefaultWorkspace if it's empty
+func NormalizeWorkspace(workspace string) string {
+	if workspace == "" {
+		return DefaultWorkspace
+	}
+	return workspace
+}
+
 type Project struct {
 	ID             string              `yaml:"id"`
 	Name           string              `yaml:"name"`
 	CreatedAt      time.Time           `yaml:"created_at"`
 	Directory      string              `yaml:"directory"`
+	Workspace      string              `yaml:"workspace,omitempty"`
 	Description    string              `yaml:"description"`
 	Tags           map[string][]string `yaml:"tags"`
 	LastSubmission time.Time           `yaml:"last_submission"`
@@ -72,6 +84,29 @@ func GetProjects() ([]Project, error) {
 	return projects, nil
 }
 
+// GetProjectWorkspace returns the terraform workspace configured for the project on the directory,
+// it's empty if there is no projects config or no project configured for the directory
+func GetProjectWorkspace(directory string) string {
+	projects, err := GetProjects()
+	if err != nil {
+		return ""
+	}
+	dir, err := filepath.Abs(directory)
+	if err != nil {
+		return ""
+	}
+	for _, p := range projects {
+		projectDir, err := filepath.Abs(p.Directory)
+		if err != nil {
+			continue
+		}
+		if projectDir == dir {
+			return p.Workspace
+		}
+	}
+	return ""
+}
+
 func WriteProjectsConfig(projects []Project) error {
 	var projectContents []string
 	for _, p := range projects {
@@ -5,4 +5,5 @@ type ProviderName string
 const (
 	AzureProvider ProviderName = "azurerm"
 	AWSProvider   ProviderName = "aws"
+	GCPProvider   ProviderName = "google"
 )
@@ -1,5 +1,7 @@
 package schema
 
+import "github.com/kaytu-io/pennywise/pkg/diagnostic"
+
 // ResourceDef is a single resource definition.
 type ResourceDef struct {
 	Address      string                 `json:"address"`
@@ -8,4 +10,17 @@ type ResourceDef struct {
 	RegionCode   string                 `json:"region_code"`
 	ProviderName ProviderName           `json:"provider_name"`
 	Values       map[string]interface{} `json:"values"`
+	// Diagnostics are the issues found while parsing the resource, its cost may be inaccurate if there are any
+	Diagnostics []diagnostic.Diagnostic `json:"diagnostics,omitempty"`
+	// UsageAddress is the address the usage of the resource is resolved with if it's not the Address, ex: the
+	// address without the directory of its terragrunt unit or the name of its CDKTF stack
+	UsageAddress string `json:"-"`
+}
+
+// GetUsageAddress returns the address the usage of the resource is resolved with
+func (r ResourceDef) GetUsageAddress() string {
+	if r.UsageAddress != "" {
+		return r.UsageAddress
+	}
+	return r.Address
 }
@@ -18,6 +18,8 @@ type SubmissionV2 struct {
 	Version    string    `json:"version"`
 	CreatedAt  time.Time `json:"created_at"`
 	ProjectId  string    `json:"project_id"`
+	Workspace  string    `json:"workspace,omitempty"`
+	Tool       *IaCTool  `json:"tool,omitempty"`
 	RootModule ModuleDef `json:"root_modules"`
 }
 
@@ -54,6 +56,7 @@ func CreateSubmissionV2(module ModuleDef) (*SubmissionV2, error) {
 
 // StoreAsFile stores the submission as a file in .pennywise/submissions directory
 func (s *SubmissionV2) StoreAsFile() error {
+	s.Workspace = NormalizeWorkspace(s.Workspace)
 	jsonData, err := json.MarshalIndent(*s, "", "  ")
 	if err != nil {
 		return err
@@ -147,15 +150,19 @@ func getAllSubmissionsV2() ([]SubmissionV2, error) {
 	return submissions, nil
 }
 
-func GetLatestSubmissionV2() (*SubmissionV2, error) {
+// GetLatestSubmissionV2 returns the latest submission made on the terraform workspace, the submissions without
+// workspace are on the default workspace
+func GetLatestSubmissionV2(workspace string) (*SubmissionV2, error) {
 	submissions, err := getAllSubmissionsV2()
 	if err != nil {
 		return nil, err
 	}
 
-	if len(submissions) == 0 {
-		return nil, fmt.Errorf("no submissions found")
+	for _, submission := range submissions {
+		if NormalizeWorkspace(submission.Workspace) == NormalizeWorkspace(workspace) {
+			return &submission, nil
+		}
 	}
 
-	return &submissions[0], nil
+	return nil, fmt.Errorf("no submissions found for workspace %s", NormalizeWorkspace(workspace))
 }
@@ -27,6 +27,8 @@ type Submission struct {
 	ID        string        `json:"id"`
 	CreatedAt time.Time     `json:"created_at"`
 	ProjectId string        `json:"project_id"`
+	Workspace string        `json:"workspace,omitempty"`
+	Tool      *IaCTool      `json:"tool,omitempty"`
 	Resources []ResourceDef `json:"resources"`
 }
 
@@ -46,6 +48,7 @@ func CreateSubmission(resources []ResourceDef) (*Submission, error) {
 
 // StoreAsFile stores the submission as a file in .pennywise/submissions directory
 func (s *Submission) StoreAsFile() error {
+	s.Workspace = NormalizeWorkspace(s.Workspace)
 	jsonData, err := json.MarshalIndent(*s, "", "  ")
 	if err != nil {
 		return err
@@ -139,15 +142,19 @@ func getAllSubmissions() ([]Submission, error) {
 	return submissions, nil
 }
 
-func GetLatestSubmission() (*Submission, error) {
+// GetLatestSubmission returns the latest submission made on the terraform workspace, the submissions without
+// workspace are on the default workspace
+func GetLatestSubmission(workspace string) (*Submission, error) {
 	submissions, err := getAllSubmissions()
 	if err != nil {
 		return nil, err
 	}
 
-	if len(submissions) == 0 {
-		return nil, fmt.Errorf("no submissions found")
+	for _, submission := range submissions {
+		if NormalizeWorkspace(submission.Workspace) == NormalizeWorkspace(workspace) {
+			return &submission, nil
+		}
 	}
 
-	return &submissions[0], nil
+	return nil, fmt.Errorf("no submissions found for workspace %s", NormalizeWorkspace(workspace))
 }
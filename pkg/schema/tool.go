@@ -0,0 +1,16 @@
+package schema
+
+const (
+	TerraformTool = "terraform"
+	OpenTofuTool  = "opentofu"
+
+	CloudFormationTool = "cloudformation"
+	ARMTool            = "arm"
+	PulumiTool         = "pulumi"
+)
+
+// IaCTool is the infrastructure as code tool (and its version) used to produce the resources of a submission
+type IaCTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
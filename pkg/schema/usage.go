@@ -0,0 +1,40 @@
+package schema
+
+import "github.com/kaytu-io/pennywise/pkg/usage"
+
+// UsageIssues returns the issues of the usage addresses that match none of the submission resources
+func (s *Submission) UsageIssues(u usage.Usage) []usage.Issue {
+	return u.CheckAddresses(resourcesAddresses(s.Resources))
+}
+
+// UsageIssues returns the issues of the usage addresses that match none of the submission resources
+func (s *SubmissionV2) UsageIssues(u usage.Usage) []usage.Issue {
+	return u.CheckAddresses(resourcesAddresses(s.GetResources()))
+}
+
+func resourcesAddresses(resources []ResourceDef) []string {
+	addresses := make([]string, 0, len(resources))
+	for _, res := range resources {
+		addresses = append(addresses, res.GetUsageAddress())
+	}
+	return addresses
+}
+
+// SetUsage sets the usage of the submission resources, so the submission can be priced with
+// another usage without parsing its resources again
+func (s *Submission) SetUsage(u usage.Usage) {
+	setResourcesUsage(s.Resources, u)
+}
+
+// SetUsage sets the usage of the submission resources, see Submission.SetUsage
+func (s *SubmissionV2) SetUsage(u usage.Usage) {
+	setResourcesUsage(s.GetResources(), u)
+}
+
+func setResourcesUsage(resources []ResourceDef, u usage.Usage) {
+	for _, res := range resources {
+		if res.Values != nil {
+			res.Values[usage.Key] = u.GetUsage(res.Type, res.GetUsageAddress())
+		}
+	}
+}
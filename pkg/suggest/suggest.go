@@ -0,0 +1,60 @@
+package suggest
+
+import (
+	"sort"
+	"strings"
+)
+
+// Closest returns at most n of the candidates closest to the name, by their edit distance to the name.
+// The candidates starting with the name are the closest ones and the candidates too far from the name are
+// not returned.
+func Closest(name string, candidates []string, n int) []string {
+	if name == "" {
+		return nil
+	}
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var closest []candidate
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		distance := Levenshtein(name, c)
+		if strings.HasPrefix(c, name) {
+			distance = 0
+		}
+		if distance <= max(2, len(name)/4) && !seen[c] {
+			seen[c] = true
+			closest = append(closest, candidate{c, distance})
+		}
+	}
+	sort.SliceStable(closest, func(i, j int) bool {
+		return closest[i].distance < closest[j].distance
+	})
+	var names []string
+	for i := 0; i < len(closest) && i < n; i++ {
+		names = append(names, closest[i].name)
+	}
+	return names
+}
+
+// Levenshtein returns the edit distance between a and b
+func Levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,123 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/kaytu-io/pennywise/pkg/cost"
+)
+
+// period is the period of the datapoints, a day
+const period = 24 * 60 * 60
+
+// Fetcher fetches the usage of the resources from their CloudWatch metrics over a lookback window
+type Fetcher struct {
+	cfg      aws.Config
+	lookback time.Duration
+	end      time.Time
+	// clients are the CloudWatch clients by region
+	clients map[string]*cloudwatch.Client
+}
+
+// NewFetcher returns a Fetcher of the metrics of the lookback window ending now, the region of the config is the
+// region of the resources whose region can't be found from their arn
+func NewFetcher(cfg aws.Config, lookback time.Duration) *Fetcher {
+	return &Fetcher{
+		cfg:      cfg,
+		lookback: lookback,
+		end:      time.Now().UTC().Truncate(time.Hour),
+		clients:  make(map[string]*cloudwatch.Client),
+	}
+}
+
+// Fetch returns the usage parameters of the resource computed from its metrics, the parameters whose metrics have
+// no datapoints over the lookback window are not returned. The metrics summed over the window (ex: the requests) are
+// extrapolated to a month of cost.HoursPerMonth hours.
+func (f *Fetcher) Fetch(ctx context.Context, res Resource) (map[string]interface{}, error) {
+	usage := make(map[string]interface{})
+	for _, m := range metrics[res.Type] {
+		namespace, dimensions, ok := m.target(res.Values)
+		if !ok {
+			continue
+		}
+		client := f.client(region(res.Values, f.cfg.Region))
+
+		var value float64
+		found := false
+		for _, name := range m.names {
+			out, err := client.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+				Namespace:  aws.String(namespace),
+				MetricName: aws.String(name),
+				Dimensions: dimensions,
+				StartTime:  aws.Time(f.end.Add(-f.lookback)),
+				EndTime:    aws.Time(f.end),
+				Period:     aws.Int32(period),
+				Statistics: []types.Statistic{m.statistic},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the %s metric %s: %w", namespace, name, err)
+			}
+			if len(out.Datapoints) == 0 {
+				continue
+			}
+			found = true
+
+			var sum float64
+			for _, d := range out.Datapoints {
+				switch m.statistic {
+				case types.StatisticSum:
+					sum += aws.ToFloat64(d.Sum)
+				default:
+					sum += aws.ToFloat64(d.Average)
+				}
+			}
+			if m.monthly {
+				value += sum * cost.HoursPerMonth.InexactFloat64() / f.lookback.Hours()
+			} else {
+				value += sum / float64(len(out.Datapoints))
+			}
+		}
+		if found {
+			usage[m.parameter] = round(value*m.scale, m.precision)
+		}
+	}
+	return usage, nil
+}
+
+// client returns the CloudWatch client of the region
+func (f *Fetcher) client(region string) *cloudwatch.Client {
+	if c, ok := f.clients[region]; ok {
+		return c
+	}
+	cfg := f.cfg.Copy()
+	cfg.Region = region
+	c := cloudwatch.NewFromConfig(cfg)
+	f.clients[region] = c
+	return c
+}
+
+// region returns the region of the resource arn (ex: arn:aws:lambda:eu-west-1:123456789012:function:api), or the
+// default region if the resource has no arn
+func region(values map[string]interface{}, defaultRegion string) string {
+	arn, _ := values["arn"].(string)
+	parts := strings.Split(arn, ":")
+	if len(parts) > 3 && parts[3] != "" {
+		return parts[3]
+	}
+	return defaultRegion
+}
+
+// round returns the value rounded to the precision, as an int if the precision is 0
+func round(value float64, precision int) interface{} {
+	if precision == 0 {
+		return int(math.Round(value))
+	}
+	p := math.Pow(10, float64(precision))
+	return math.Round(value*p) / p
+}
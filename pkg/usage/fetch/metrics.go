@@ -0,0 +1,151 @@
+package fetch
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// bytesPerGB is the number of bytes of the GB used by the AWS pricing
+const bytesPerGB = 1 << 30
+
+// metric is the CloudWatch metric a usage parameter of a resource type is computed from
+type metric struct {
+	parameter string
+	// names are the names of the metrics summed, ex: the bytes from the sources and destinations of a NAT gateway
+	names     []string
+	statistic types.Statistic
+	// target returns the namespace and dimensions of the metric of the resource, false if the resource values
+	// don't have them
+	target func(values map[string]interface{}) (string, []types.Dimension, bool)
+	// monthly is true if the metric is summed over the lookback window and extrapolated to a month, the
+	// other metrics are averaged over the window
+	monthly bool
+	// scale multiplies the value of the metric, ex: from bytes to GB
+	scale float64
+	// precision is the number of decimal places of the value, 0 for integers
+	precision int
+}
+
+// metrics are the metrics of the usage parameters by resource type
+var metrics = map[string][]metric{
+	"aws_lambda_function": {
+		{
+			parameter: "monthly_requests",
+			names:     []string{"Invocations"},
+			statistic: types.StatisticSum,
+			target:    dimension("AWS/Lambda", "FunctionName", "function_name"),
+			monthly:   true,
+			scale:     1,
+		},
+		{
+			parameter: "request_duration_ms",
+			names:     []string{"Duration"},
+			statistic: types.StatisticAverage,
+			target:    dimension("AWS/Lambda", "FunctionName", "function_name"),
+			scale:     1,
+		},
+	},
+	"aws_nat_gateway": {
+		{
+			parameter: "monthly_data_processed_gb",
+			names:     []string{"BytesInFromSource", "BytesInFromDestination"},
+			statistic: types.StatisticSum,
+			target:    dimension("AWS/NATGateway", "NatGatewayId", "id"),
+			monthly:   true,
+			scale:     1.0 / bytesPerGB,
+			precision: 2,
+		},
+	},
+	"aws_lb":  loadBalancerMetrics,
+	"aws_alb": loadBalancerMetrics,
+	"aws_elb": {
+		{
+			parameter: "monthly_data_processed_gb",
+			names:     []string{"EstimatedProcessedBytes"},
+			statistic: types.StatisticSum,
+			target:    dimension("AWS/ELB", "LoadBalancerName", "name"),
+			monthly:   true,
+			scale:     1.0 / bytesPerGB,
+			precision: 2,
+		},
+	},
+	"aws_efs_file_system": {
+		{
+			parameter: "storage_gb",
+			names:     []string{"StorageBytes"},
+			statistic: types.StatisticAverage,
+			target:    efsStorage("Standard"),
+			scale:     1.0 / bytesPerGB,
+			precision: 2,
+		},
+		{
+			parameter: "infrequent_access_storage_gb",
+			names:     []string{"StorageBytes"},
+			statistic: types.StatisticAverage,
+			target:    efsStorage("IA"),
+			scale:     1.0 / bytesPerGB,
+			precision: 2,
+		},
+	},
+}
+
+// loadBalancerMetrics are the metrics of the application and network load balancers
+var loadBalancerMetrics = []metric{
+	{
+		parameter: "monthly_data_processed_gb",
+		names:     []string{"ProcessedBytes"},
+		statistic: types.StatisticSum,
+		target:    loadBalancer,
+		monthly:   true,
+		scale:     1.0 / bytesPerGB,
+		precision: 2,
+	},
+}
+
+// dimension returns the target of the metrics of the namespace with the dimension set to the value of the attribute
+func dimension(namespace, name, attribute string) func(map[string]interface{}) (string, []types.Dimension, bool) {
+	return func(values map[string]interface{}) (string, []types.Dimension, bool) {
+		value, ok := values[attribute].(string)
+		if !ok || value == "" {
+			return "", nil, false
+		}
+		return namespace, []types.Dimension{{Name: aws.String(name), Value: aws.String(value)}}, true
+	}
+}
+
+// loadBalancer returns the target of the metrics of an application or network load balancer, their dimension is
+// the end of their arn, ex: app/web/50dc6c495c0c9188
+func loadBalancer(values map[string]interface{}) (string, []types.Dimension, bool) {
+	arn, _ := values["arn"].(string)
+	_, name, ok := strings.Cut(arn, ":loadbalancer/")
+	if !ok {
+		return "", nil, false
+	}
+	namespace := "AWS/ApplicationELB"
+	switch values["load_balancer_type"] {
+	case "network":
+		namespace = "AWS/NetworkELB"
+	case "gateway":
+		return "", nil, false
+	}
+	return namespace, []types.Dimension{{Name: aws.String("LoadBalancer"), Value: aws.String(name)}}, true
+}
+
+// efsStorage returns the target of the storage metric of the storage class of an EFS file system
+func efsStorage(storageClass string) func(map[string]interface{}) (string, []types.Dimension, bool) {
+	return func(values map[string]interface{}) (string, []types.Dimension, bool) {
+		namespace, dimensions, ok := dimension("AWS/EFS", "FileSystemId", "id")(values)
+		if !ok {
+			return "", nil, false
+		}
+		return namespace, append(dimensions, types.Dimension{Name: aws.String("StorageClass"), Value: aws.String(storageClass)}), true
+	}
+}
+
+// Supported returns true if the usage of the resource type can be fetched from CloudWatch
+func Supported(resourceType string) bool {
+	_, ok := metrics[resourceType]
+	return ok
+}
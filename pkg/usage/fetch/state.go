@@ -0,0 +1,51 @@
+package fetch
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kaytu-io/pennywise/pkg/parser/terraform"
+)
+
+// Resource is a resource deployed by terraform, with its values in the state (ex: its id and arn)
+type Resource struct {
+	Address string
+	Type    string
+	Values  map[string]interface{}
+}
+
+// StateResources returns the managed resources of a terraform state in JSON format (terraform show -json), or of
+// the prior state of a terraform plan in JSON format, which is the state the plan was made from
+func StateResources(content []byte) ([]Resource, error) {
+	var doc struct {
+		PriorState *terraform.State  `json:"prior_state"`
+		Values     *terraform.Values `json:"values"`
+	}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse the state: %w", err)
+	}
+	var values *terraform.Values
+	switch {
+	case doc.PriorState != nil:
+		values = &doc.PriorState.Values
+	case doc.Values != nil:
+		values = doc.Values
+	default:
+		return nil, fmt.Errorf("no resources deployed, the plan has no prior state")
+	}
+	return moduleResources(&values.RootModule), nil
+}
+
+func moduleResources(module *terraform.Module) []Resource {
+	var resources []Resource
+	for _, res := range module.Resources {
+		if res.Mode != "managed" {
+			continue
+		}
+		resources = append(resources, Resource{Address: res.Address, Type: res.Type, Values: res.Values})
+	}
+	for _, child := range module.ChildModules {
+		resources = append(resources, moduleResources(child)...)
+	}
+	return resources
+}
@@ -0,0 +1,102 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ProfilesKey is the key of the usage profiles in the usage files
+const ProfilesKey = "profiles"
+
+// File is a usage file, with the usage of the resources and the named profiles overriding it
+type File struct {
+	Usage Usage
+	// Profiles are the usage profiles (ex: low, expected and peak) in the order of the file
+	Profiles []Profile
+}
+
+// Profile is a named usage, its usage overrides the usage of its file when the profile is used
+type Profile struct {
+	Name  string
+	Usage Usage
+}
+
+// ReadFile reads the usage file on the path, the file can be in json or yaml format. The usage profiles are under
+// the profiles key of the file. The usage parameters that are not supported by their resource type or have a wrong
+// type are reported as warnings.
+func ReadFile(path string) (File, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, fmt.Errorf("error while reading usage file %s", err)
+	}
+
+	var usage Usage
+	ext := filepath.Ext(path)
+	switch ext {
+	case ".json":
+		err = json.Unmarshal(content, &usage)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &usage)
+	default:
+		return File{}, fmt.Errorf("unsupported file format %s for usage file", ext)
+	}
+	if err != nil {
+		return File{}, fmt.Errorf("error while parsing usage file %s", err)
+	}
+	file := File{Usage: usage}
+	if _, ok := usage[ProfilesKey]; ok {
+		delete(usage, ProfilesKey)
+		file.Profiles, err = parseProfiles(content)
+		if err != nil {
+			return File{}, fmt.Errorf("error while parsing usage profiles %s", err)
+		}
+	}
+
+	Warn(file.Usage.Validate())
+	for _, p := range file.Profiles {
+		issues := p.Usage.Validate()
+		for i := range issues {
+			issues[i].Key = fmt.Sprintf("%s.%s.%s", ProfilesKey, p.Name, issues[i].Key)
+		}
+		Warn(issues)
+	}
+	return file, nil
+}
+
+// Profile returns the usage of the profile of the file, false if the file has no such profile
+func (f File) Profile(name string) (Usage, bool) {
+	for _, p := range f.Profiles {
+		if p.Name == name {
+			return p.Usage, true
+		}
+	}
+	return nil, false
+}
+
+// parseProfiles returns the profiles of the usage file content in their order, json is parsed as yaml
+// which keeps the order of the keys
+func parseProfiles(content []byte) ([]Profile, error) {
+	var file struct {
+		Profiles yaml.MapSlice `yaml:"profiles"`
+	}
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	var profiles []Profile
+	for _, item := range file.Profiles {
+		profileContent, err := yaml.Marshal(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		var usage Usage
+		if err := yaml.Unmarshal(profileContent, &usage); err != nil {
+			return nil, fmt.Errorf("profile %v: %w", item.Key, err)
+		}
+		profiles = append(profiles, Profile{Name: fmt.Sprint(item.Key), Usage: usage})
+	}
+	return profiles, nil
+}
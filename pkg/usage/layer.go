@@ -0,0 +1,76 @@
+package usage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Layer is a usage applied over the usages of the layers before it, ex: the usage file of the project
+// applied over the usage file of the organization
+type Layer struct {
+	// Name is the name of the layer shown as the source of the usage values, ex: project
+	Name  string
+	Usage Usage
+}
+
+// layerValue is a value of a layered usage with the index of the layer it comes from
+type layerValue struct {
+	layer int
+	value interface{}
+}
+
+// Layered returns the usage of the layers, the usage of every layer overrides the usage of the layers before it.
+// The values keep their layer so GetUsage resolves them by layer first, and by the specificity of their keys only
+// within a layer: a --usage-set aws_instance.monthly_hrs overrides aws_instance.web of the usage file.
+func Layered(layers []Layer) Usage {
+	usage := make(Usage)
+	for i, l := range layers {
+		for key, values := range l.Usage {
+			if usage[key] == nil {
+				usage[key] = make(map[string]interface{}, len(values))
+			}
+			for name, v := range values {
+				usage[key][name] = layerValue{layer: i, value: v}
+			}
+		}
+	}
+	return usage
+}
+
+// unwrapLayer returns the value and the index of its layer, the values of a usage that isn't layered are in the layer 0
+func unwrapLayer(v interface{}) (interface{}, int) {
+	if lv, ok := v.(layerValue); ok {
+		return lv.value, lv.layer
+	}
+	return v, 0
+}
+
+// ParseSet returns the usage of the values in the key.parameter=value format, ex: aws_instance.web.monthly_hrs=100.
+// The values are numbers if they can be parsed as numbers, and strings otherwise.
+func ParseSet(values map[string]string) (Usage, error) {
+	usage := make(Usage)
+	for keyParameter, value := range values {
+		i := strings.LastIndex(keyParameter, ".")
+		if i <= 0 || i == len(keyParameter)-1 {
+			return nil, fmt.Errorf("invalid usage %q, expected key.parameter=value", keyParameter)
+		}
+		key, name := keyParameter[:i], keyParameter[i+1:]
+		if usage[key] == nil {
+			usage[key] = make(map[string]interface{})
+		}
+		usage[key][name] = parseValue(value)
+	}
+	return usage, nil
+}
+
+// parseValue returns the value as an int or a float if it's a number
+func parseValue(value string) interface{} {
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return int(i)
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
@@ -0,0 +1,207 @@
+package usage
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// keyKind is the kind of a usage key, the kinds are in the order of their specificity: the usage of a key
+// overrides the usage of the less specific keys matching the same resource
+type keyKind int
+
+const (
+	// typeKey is a resource type, ex: aws_instance
+	typeKey keyKind = iota
+	// regexKey is a regular expression between slashes matching the addresses, ex: /^aws_instance\.(web|api)/
+	regexKey
+	// globKey is an address with * (any characters but a dot) and ? (any character but a dot), ex: module.*.aws_nat_gateway.*
+	globKey
+	// moduleKey is a module, its usage applies to all the resources of the module and of its child modules, ex: module.app
+	moduleKey
+	// moduleTypeKey is a resource type in a module and its child modules, ex: module.app.aws_instance
+	moduleTypeKey
+	// resourceKey is an address without index, its usage applies to all the instances of the resource, ex: aws_instance.web
+	resourceKey
+	// instanceKey is the address of an instance, ex: aws_instance.web[0] or aws_instance.web["a"]
+	instanceKey
+)
+
+// forEachIndexRegex matches the indexes of the addresses with the for_each key quoted or not, ex: [0], ["a"], ['a'] or [a]
+var forEachIndexRegex = regexp.MustCompile(`\[("[^"]*"|'[^']*'|[^\]]*)\]`)
+
+// patterns caches the regular expressions of the regex and glob keys, the value is nil if the key is not valid
+var patterns sync.Map
+
+// MatchingKeys returns the keys of the usage matching the resource rt (ex: aws_instance) and address, from the least
+// to the most specific. The keys of the same kind are ordered by their length, as longer keys are more specific.
+func (u Usage) MatchingKeys(rt, addr string) []string {
+	var keys []string
+	for key := range u {
+		if matches(key, rt, addr) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ki, kj := kindOf(keys[i]), kindOf(keys[j])
+		if ki != kj {
+			return ki < kj
+		}
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) < len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// kindOf returns the kind of the usage key
+func kindOf(key string) keyKind {
+	if isRegex(key) {
+		return regexKey
+	}
+	if strings.ContainsAny(key, "*?") {
+		return globKey
+	}
+	modulePath, rest := splitModules(key)
+	switch {
+	case len(rest) == 0 && modulePath != "":
+		return moduleKey
+	case len(rest) <= 1 && modulePath != "":
+		return moduleTypeKey
+	case len(rest) <= 1:
+		return typeKey
+	case TrimIndexes(key) == key:
+		return resourceKey
+	default:
+		return instanceKey
+	}
+}
+
+// matches returns true if the usage of the key applies to the resource rt and address
+func matches(key, rt, addr string) bool {
+	addr = NormalizeIndexes(addr)
+	trimmed := TrimIndexes(addr)
+	switch kindOf(key) {
+	case typeKey:
+		return key == rt
+	case moduleKey:
+		return inModule(NormalizeIndexes(key), addr, trimmed)
+	case moduleTypeKey:
+		modulePath, rest := splitModules(NormalizeIndexes(key))
+		return rest[0] == rt && inModule(modulePath, addr, trimmed)
+	case regexKey, globKey:
+		re := pattern(key)
+		return re != nil && (re.MatchString(addr) || re.MatchString(trimmed))
+	case resourceKey:
+		return key == trimmed
+	default:
+		key = NormalizeIndexes(key)
+		return key == addr || key == trimLastIndex(addr)
+	}
+}
+
+// inModule returns true if the address, with or without its indexes, is in the module or in one of its child modules
+func inModule(modulePath, addr, trimmed string) bool {
+	return strings.HasPrefix(addr, modulePath+".") || strings.HasPrefix(trimmed, modulePath+".")
+}
+
+// isRegex returns true if the key is a regular expression between slashes
+func isRegex(key string) bool {
+	return len(key) > 2 && strings.HasPrefix(key, "/") && strings.HasSuffix(key, "/")
+}
+
+// pattern returns the regular expression of a regex or glob key, nil if the key is not a valid regular expression
+func pattern(key string) *regexp.Regexp {
+	if re, ok := patterns.Load(key); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, _ := compilePattern(key)
+	patterns.Store(key, re)
+	return re
+}
+
+// compilePattern compiles the regular expression of the regex key, or the one of the glob key matching whole addresses
+func compilePattern(key string) (*regexp.Regexp, error) {
+	if isRegex(key) {
+		return regexp.Compile(key[1 : len(key)-1])
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range key {
+		switch r {
+		case '*':
+			b.WriteString(`[^.]*`)
+		case '?':
+			b.WriteString(`[^.]`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// splitModules returns the module path of the address (ex: module.app.module.db) and the segments after it
+// (ex: aws_instance and web[0]), the dots of the for_each keys don't split the segments
+func splitModules(address string) (string, []string) {
+	segments := splitAddress(address)
+	i := 0
+	for i+1 < len(segments) && segments[i] == "module" {
+		i += 2
+	}
+	return strings.Join(segments[:i], "."), segments[i:]
+}
+
+// splitAddress returns the segments of the address separated by dots outside the indexes
+func splitAddress(address string) []string {
+	var segments []string
+	depth, start := 0, 0
+	for i, r := range address {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				segments = append(segments, address[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if address == "" {
+		return nil
+	}
+	return append(segments, address[start:])
+}
+
+// NormalizeIndexes returns the address with its for_each keys quoted as terraform does, so aws_instance.web[a]
+// and aws_instance.web['a'] are the address aws_instance.web["a"]
+func NormalizeIndexes(address string) string {
+	return forEachIndexRegex.ReplaceAllStringFunc(address, func(index string) string {
+		key := index[1 : len(index)-1]
+		if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+			return `["` + key[1:len(key)-1] + `"]`
+		}
+		if _, err := strconv.Atoi(key); err == nil {
+			return index
+		}
+		return `["` + key + `"]`
+	})
+}
+
+// trimLastIndex returns the address without the index of the resource, the indexes of its modules are kept
+func trimLastIndex(address string) string {
+	segments := splitAddress(address)
+	if len(segments) == 0 {
+		return address
+	}
+	last := segments[len(segments)-1]
+	if i := strings.Index(last, "["); i >= 0 {
+		return address[:len(address)-len(last)+i]
+	}
+	return address
+}
@@ -0,0 +1,46 @@
+package usage
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+)
+
+//go:embed schema.json
+var schemaContent []byte
+
+// Parameter is a usage parameter supported by a resource type
+type Parameter struct {
+	// Type is the type of the values of the parameter, number or string
+	Type string `json:"type"`
+	// Unit is the unit of the number values, ex: GB or hours
+	Unit string `json:"unit,omitempty"`
+	// Values are the values the string parameters can have
+	Values      []string `json:"values,omitempty"`
+	Description string   `json:"description"`
+}
+
+// parameters are the usage parameters supported by every resource type
+var parameters map[string]map[string]Parameter
+
+func init() {
+	if err := json.Unmarshal(schemaContent, &parameters); err != nil {
+		panic(err)
+	}
+}
+
+// Parameters returns the usage parameters supported by the resource type (ex: aws_instance)
+func Parameters(resourceType string) (map[string]Parameter, bool) {
+	p, ok := parameters[resourceType]
+	return p, ok
+}
+
+// ResourceTypes returns the resource types that support usage parameters, sorted by name
+func ResourceTypes() []string {
+	types := make([]string, 0, len(parameters))
+	for t := range parameters {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
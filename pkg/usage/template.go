@@ -0,0 +1,116 @@
+package usage
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CheckTemplatePath returns an error if the template can't be written to the usage file of the path, the templates
+// are yaml and a json usage file would not be readable anymore
+func CheckTemplatePath(path string) error {
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		return nil
+	default:
+		return fmt.Errorf("unsupported file format %s for usage file template, the template is written in yaml (.yml or .yaml)", ext)
+	}
+}
+
+// Template returns a commented yaml usage file with the usage parameters of the resources, given as their types
+// by their addresses. The parameters are commented out with their default values, ex: from Default, unless they
+// have a value in the existing usage file which is kept. The existing usages of other keys and the profiles are
+// kept as well.
+func Template(resources map[string]string, existingFile File) []byte {
+	existing := existingFile.Usage
+	var b bytes.Buffer
+	b.WriteString("# Usage file of the resources with usage-based costs, generated by pennywise.\n")
+	b.WriteString("# Uncomment the parameters and set the expected usage of the resources, the values that\n")
+	b.WriteString("# are commented out are the defaults (0 or the first value if the parameter has none).\n")
+
+	kept := make(map[string]bool)
+	for _, address := range sortedKeys(resources) {
+		resourceType := resources[address]
+		params, ok := Parameters(resourceType)
+		if !ok {
+			continue
+		}
+		values := existing[address]
+		kept[address] = values != nil
+
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("%s:\n", yamlScalar(address)))
+		for _, name := range sortedKeys(params) {
+			param := params[name]
+			b.WriteString(fmt.Sprintf("  # %s\n", param.comment()))
+			if v, ok := values[name]; ok {
+				b.WriteString(fmt.Sprintf("  %s: %s\n", name, yamlScalar(v)))
+				continue
+			}
+			b.WriteString(fmt.Sprintf("  # %s: %s\n", name, yamlScalar(defaultValue(resourceType, name, param))))
+		}
+		// The values of the parameters that are not known are kept, they are reported when the file is read
+		for _, name := range sortedKeys(values) {
+			if _, ok := params[name]; !ok {
+				b.WriteString(fmt.Sprintf("  %s: %s\n", name, yamlScalar(values[name])))
+			}
+		}
+	}
+
+	others := make(Usage)
+	for key, values := range existing {
+		if !kept[key] {
+			others[key] = values
+		}
+	}
+	if len(others) > 0 {
+		content, _ := yaml.Marshal(others)
+		b.WriteString("\n# Usages of the existing usage file that are not for a resource of the project\n")
+		b.Write(content)
+	}
+
+	if len(existingFile.Profiles) > 0 {
+		profiles := make(yaml.MapSlice, 0, len(existingFile.Profiles))
+		for _, p := range existingFile.Profiles {
+			profiles = append(profiles, yaml.MapItem{Key: p.Name, Value: p.Usage})
+		}
+		content, _ := yaml.Marshal(yaml.MapSlice{{Key: ProfilesKey, Value: profiles}})
+		b.WriteString("\n# Usage profiles, used with --usage-profile or --scenarios\n")
+		b.Write(content)
+	}
+	return b.Bytes()
+}
+
+// comment returns the description of the parameter with its unit or values
+func (p Parameter) comment() string {
+	switch {
+	case p.Unit != "" && !strings.HasSuffix(strings.ToLower(p.Description), strings.ToLower(p.Unit)):
+		return fmt.Sprintf("%s (%s)", p.Description, p.Unit)
+	case len(p.Values) > 0:
+		return fmt.Sprintf("%s, one of %s", p.Description, strings.Join(p.Values, ", "))
+	}
+	return p.Description
+}
+
+// defaultValue returns the default value of the parameter of the resource type from Default, or a zero value
+func defaultValue(resourceType, name string, param Parameter) interface{} {
+	if v, ok := Default[resourceType][name]; ok {
+		return v
+	}
+	if len(param.Values) > 0 {
+		return param.Values[0]
+	}
+	return 0
+}
+
+// yamlScalar returns the value formatted as a yaml scalar, quoted if needed (ex: the addresses with a for_each key)
+func yamlScalar(v interface{}) string {
+	content, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(string(content))
+}
@@ -1,7 +1,5 @@
 package usage
 
-import "strings"
-
 const (
 	// Key is the key used to set the usage
 	// on the values passed to the resources
@@ -55,28 +53,58 @@ var Default = Usage{
 		"monthly_hours": 730,
 	},
 	"azurerm_lb": map[string]interface{}{
-		"monthly_data_proceed": 1000,
+		"monthly_data_processed_gb": 1000,
 	},
 }
 
 // Usage is the struct defining all the configure usages
 type Usage map[string]map[string]interface{}
 
-// GetUsage will return the usage from the resource rt (ex: aws_instance)
+// GetUsage will return the usage from the resource rt (ex: aws_instance) and address, merging the usage of
+// all the keys matching the resource: the usage of a key overrides the usage of the less specific keys
+// (see MatchingKeys), ex: aws_instance.web[0] overrides aws_instance.web which overrides aws_instance.
+// The values of a layered usage are resolved by layer first (see Layered).
 func (u Usage) GetUsage(rt string, addr string) map[string]interface{} {
-	us, ok := u[addr]
-	if ok {
-		return us
-	}
-	addr = strings.Split(addr, "[")[0]
-	us, ok = u[addr]
-	if ok {
-		return us
+	origins := u.resolve(rt, addr)
+	if origins == nil {
+		return nil
 	}
-	us, ok = u[rt]
-	if ok {
-		return us
+	usage := make(map[string]interface{}, len(origins))
+	for name, o := range origins {
+		usage[name] = o.value
 	}
+	return usage
+}
+
+// Source returns the key and the index of the layer the value of the usage parameter of the resource
+// rt and address comes from, the key is empty if no key sets the parameter
+func (u Usage) Source(rt, addr, name string) (string, int) {
+	o := u.resolve(rt, addr)[name]
+	return o.key, o.layer
+}
+
+// origin is a value of a usage parameter with the key and the layer it comes from
+type origin struct {
+	key   string
+	layer int
+	value interface{}
+}
 
-	return nil
+// resolve returns the values of the usage parameters of the resource, nil if no key matches the resource. The keys
+// are from the least to the most specific so a key overrides the keys before it of its layer and of the lower layers.
+func (u Usage) resolve(rt, addr string) map[string]origin {
+	var origins map[string]origin
+	for _, key := range u.MatchingKeys(rt, addr) {
+		if origins == nil {
+			origins = make(map[string]origin)
+		}
+		for name, v := range u[key] {
+			value, layer := unwrapLayer(v)
+			if o, ok := origins[name]; ok && o.layer > layer {
+				continue
+			}
+			origins[name] = origin{key: key, layer: layer, value: value}
+		}
+	}
+	return origins
 }
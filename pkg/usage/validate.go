@@ -0,0 +1,166 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kaytu-io/pennywise/pkg/suggest"
+)
+
+// maxSuggestions is the maximum number of parameters suggested for an unknown parameter
+const maxSuggestions = 3
+
+// indexRegex matches the count and for_each indexes of the addresses, ex: [0] or ["a"]
+var indexRegex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// Issue is a problem found on the usage, its parameters are not used by the estimation
+type Issue struct {
+	// Key is the resource type or address the usage is defined for
+	Key string
+	// Parameter is the usage parameter with the issue, empty if the issue is about the key
+	Parameter string
+	Message   string
+}
+
+// String returns the issue as a message for the key and its parameter
+func (i Issue) String() string {
+	if i.Parameter == "" {
+		return fmt.Sprintf("%s: %s", i.Key, i.Message)
+	}
+	return fmt.Sprintf("%s.%s: %s", i.Key, i.Parameter, i.Message)
+}
+
+// Warn prints the issues found on the usage file to the stderr
+func Warn(issues []Issue) {
+	for _, i := range issues {
+		fmt.Fprintf(os.Stderr, "warning: usage file: %s\n", i)
+	}
+}
+
+// Validate returns the issues of the usage parameters that are not supported by the resource type of their key or
+// whose value doesn't have the type of the parameter
+func (u Usage) Validate() []Issue {
+	var issues []Issue
+	for _, key := range sortedKeys(u) {
+		if isRegex(key) {
+			if _, err := compilePattern(key); err != nil {
+				issues = append(issues, Issue{Key: key, Message: fmt.Sprintf("invalid regular expression: %s", err)})
+				continue
+			}
+		}
+		// The keys of modules and the patterns may match resources of any type
+		resourceType := ResourceType(key)
+		if resourceType == "" {
+			continue
+		}
+		params, ok := Parameters(resourceType)
+		if !ok {
+			issues = append(issues, Issue{Key: key, Message: fmt.Sprintf("resource type %s has no usage parameters", resourceType)})
+			continue
+		}
+		names := make([]string, 0, len(params))
+		for name := range params {
+			names = append(names, name)
+		}
+		for _, name := range sortedKeys(u[key]) {
+			param, ok := params[name]
+			if !ok {
+				message := fmt.Sprintf("unknown usage parameter of %s", resourceType)
+				if suggestions := suggest.Closest(name, names, maxSuggestions); len(suggestions) > 0 {
+					message = fmt.Sprintf("%s, did you mean %s?", message, strings.Join(suggestions, ", "))
+				}
+				issues = append(issues, Issue{Key: key, Parameter: name, Message: message})
+				continue
+			}
+			if err := param.check(u[key][name]); err != nil {
+				issues = append(issues, Issue{Key: key, Parameter: name, Message: err.Error()})
+			}
+		}
+	}
+	return issues
+}
+
+// check returns an error if the value doesn't have the type of the parameter
+func (p Parameter) check(value interface{}) error {
+	switch p.Type {
+	case "number":
+		switch value.(type) {
+		case int, int64, float64:
+			return nil
+		}
+		return fmt.Errorf("%#v is not a number", value)
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%#v is not a string", value)
+		}
+		if len(p.Values) > 0 && !slices.Contains(p.Values, s) {
+			return fmt.Errorf("%q is not one of %s", s, strings.Join(p.Values, ", "))
+		}
+	}
+	return nil
+}
+
+// CheckAddresses returns the issues of the usage keys that are not resource types and match none of the addresses,
+// the keys are matched as the usage is resolved by GetUsage
+func (u Usage) CheckAddresses(addresses []string) []Issue {
+	var issues []Issue
+	for _, key := range sortedKeys(u) {
+		kind := kindOf(key)
+		// The invalid regular expressions are reported by Validate
+		if kind == typeKey || (kind == regexKey && pattern(key) == nil) {
+			continue
+		}
+		matched := false
+		for _, addr := range addresses {
+			if matched = matches(key, ResourceType(addr), addr); matched {
+				break
+			}
+		}
+		switch {
+		case matched:
+		case kind == resourceKey || kind == instanceKey:
+			issues = append(issues, Issue{Key: key, Message: "no resource has this address"})
+		default:
+			issues = append(issues, Issue{Key: key, Message: "no resource matches this key"})
+		}
+	}
+	return issues
+}
+
+// TrimIndexes returns the address without its count and for_each indexes, ex: aws_instance.web for aws_instance.web[0]
+func TrimIndexes(address string) string {
+	return indexRegex.ReplaceAllString(address, "")
+}
+
+// ResourceType returns the resource type of the usage key, ex: aws_instance for module.app.aws_instance.web[0], it's
+// empty if the key may match resources of any type (ex: module.app, *.web or /^aws_/)
+func ResourceType(key string) string {
+	if isRegex(key) {
+		return ""
+	}
+	var resourceType string
+	switch _, rest := splitModules(key); {
+	case len(rest) == 1:
+		resourceType = rest[0]
+	case len(rest) > 1:
+		resourceType = rest[len(rest)-2]
+	}
+	if strings.ContainsAny(resourceType, "*?") {
+		return ""
+	}
+	return resourceType
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}